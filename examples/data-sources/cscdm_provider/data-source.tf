@@ -0,0 +1,6 @@
+# Report which provider build is in use.
+data "cscdm_provider" "current" {}
+
+output "cscdm_provider_version" {
+  value = data.cscdm_provider.current.version
+}
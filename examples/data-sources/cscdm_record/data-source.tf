@@ -0,0 +1,6 @@
+# Look up a single record by key.
+data "cscdm_record" "www" {
+  zone = "example.com"
+  type = "A"
+  key  = "www"
+}
@@ -0,0 +1,6 @@
+# Look up a single record by its CSC id, as used when importing.
+data "cscdm_record_by_id" "www" {
+  zone = "example.com"
+  type = "A"
+  id   = "12345"
+}
@@ -0,0 +1,9 @@
+# Render every record in a zone as cscdm_record resources to adopt it.
+data "cscdm_zone_hcl" "example" {
+  zone = "example.com"
+}
+
+resource "local_file" "example_records" {
+  filename = "${path.module}/example_com.tf"
+  content  = data.cscdm_zone_hcl.example.hcl
+}
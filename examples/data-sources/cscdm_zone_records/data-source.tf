@@ -0,0 +1,8 @@
+# Read every record in a zone as a map suited to for_each.
+data "cscdm_zone_records" "example" {
+  zone = "example.com"
+}
+
+output "cscdm_a_record_values" {
+  value = [for r in data.cscdm_zone_records.example.records : r.value if r.type == "A"]
+}
@@ -1,2 +1,15 @@
 # List all DNS zones.
 data "cscdm_zones" "all" {}
+
+# List zones with an MX record pointing at a legacy mail host.
+data "cscdm_zones" "legacy_mail" {
+  record_filter = {
+    type  = "MX"
+    value = "mail.legacy.example.com"
+  }
+}
+
+# Read a few known zones without listing every zone.
+data "cscdm_zones" "selected" {
+  names = ["example.com", "example.org"]
+}
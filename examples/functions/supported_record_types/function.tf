@@ -0,0 +1,4 @@
+# List the record types cscdm_record can manage. Requires Terraform 1.8 or later.
+output "cscdm_supported_record_types" {
+  value = provider::cscdm::supported_record_types()
+}
@@ -0,0 +1,5 @@
+# Import by CSC record id.
+terraform import cscdm_record.www_example_com example.com:A:123456
+
+# Import by record key and value.
+terraform import cscdm_record.www_example_com example.com:A:www:127.0.0.1
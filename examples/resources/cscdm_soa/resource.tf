@@ -0,0 +1,8 @@
+resource "cscdm_soa" "example_com" {
+  zone_name  = "example.com"
+  refresh    = 3600
+  retry      = 900
+  expire     = 1209600
+  ttl_min    = 300
+  tech_email = "hostmaster@example.com"
+}
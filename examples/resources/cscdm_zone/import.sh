@@ -0,0 +1,2 @@
+# Import by zone name.
+terraform import cscdm_zone.example_com example.com
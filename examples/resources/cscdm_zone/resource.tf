@@ -0,0 +1,4 @@
+resource "cscdm_zone" "example_com" {
+  zone_name    = "example.com"
+  hosting_type = "MANAGED"
+}
@@ -0,0 +1,94 @@
+package cscdm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// Backoff returns the delay before retry number attempt (counting from 0):
+// base doubled once per attempt, never exceeding limit.
+func Backoff(base time.Duration, limit time.Duration, attempt int) time.Duration {
+	return ScaledBackoff(base, limit, 2, attempt)
+}
+
+// ScaledBackoff returns the delay before retry number attempt (counting from
+// 0): base multiplied by multiplier once per attempt, never exceeding limit.
+// Multipliers below 1 are treated as 1.
+func ScaledBackoff(base time.Duration, limit time.Duration, multiplier float64, attempt int) time.Duration {
+	multiplier = max(multiplier, 1)
+
+	delay := base
+	for i := 0; i < attempt && delay > 0 && delay < limit; i++ {
+		delay = time.Duration(float64(delay) * multiplier)
+	}
+
+	if delay > limit || delay <= 0 {
+		return limit
+	}
+
+	return delay
+}
+
+// Jitter returns a random delay between half of d and d, so clients backing
+// off together do not retry in step.
+func Jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+
+	return d - rand.N(d/2)
+}
+
+func (c *Client) retryDelay(attempt int) time.Duration {
+	return Jitter(ScaledBackoff(c.PollInterval, c.MaxBackoff, c.BackoffMultiplier, attempt))
+}
+
+// RetryPolicy decides whether a failed request is retried and after what
+// delay. attempt counts from 0, statusCode is 0 when no response was
+// received, and err describes the failure, wrapping a *ZoneEditErr when CSC
+// returned one.
+type RetryPolicy func(attempt int, statusCode int, err error) (retry bool, delay time.Duration)
+
+// defaultRetryPolicy retries CSC's OPEN_ZONE_EDITS rejection until
+// OpenZoneEditsAttempts submissions have been made, and rate limiting up to
+// MAX_RETRY_ATTEMPTS times, with jittered, capped exponential backoff. Server
+// errors are not retried: a zone edit may already have been accepted by the
+// time a gateway answers 502 or 504, and GETs are retried by the transport.
+func (c *Client) defaultRetryPolicy(attempt int, statusCode int, err error) (bool, time.Duration) {
+	var zeErr *ZoneEditErr
+	if errors.As(err, &zeErr) && zeErr.Code == "OPEN_ZONE_EDITS" {
+		return attempt+1 < c.OpenZoneEditsAttempts, c.retryDelay(attempt)
+	}
+
+	if statusCode == http.StatusTooManyRequests && attempt < MAX_RETRY_ATTEMPTS {
+		return true, c.retryDelay(attempt)
+	}
+
+	return false, 0
+}
+
+// shouldRetry consults the client's RetryPolicy, or the default policy when
+// none is set.
+func (c *Client) shouldRetry(attempt int, statusCode int, err error) (bool, time.Duration) {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy(attempt, statusCode, err)
+	}
+
+	return c.defaultRetryPolicy(attempt, statusCode, err)
+}
+
+// sleepContext waits for d, returning early with ctx's error if it ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
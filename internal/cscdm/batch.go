@@ -1,54 +1,277 @@
 package cscdm
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// warnOutput receives the client's log lines. Terraform shows provider
+// stderr prefixed with [WARN] in its warning logs.
+var warnOutput io.Writer = os.Stderr
 
 // Record represents a planned DNS record.
 type RecordAction struct {
 	ZoneEdit
 	ZoneName string
+
+	// requeues counts the flushes this action was re-queued from after a
+	// zone lock conflict.
+	requeues int
+	// unreachableSince is when the action was first held back because CSC
+	// could not be reached, zero while it never has been.
+	unreachableSince time.Time
+	// ctx is the context of the caller waiting on the action, nil for
+	// actions queued without one.
+	ctx context.Context
+	// errorChan is the channel the action's caller waits on, telling it
+	// apart from a later caller for the same record.
+	errorChan chan error
+}
+
+// abandoned reports whether the caller waiting on the action has given up.
+func (ra *RecordAction) abandoned() bool {
+	return ra.ctx != nil && ra.ctx.Err() != nil
 }
 
-func (c *Client) enqueue(recordAction *RecordAction, returnChan chan *ZoneRecord, errorChan chan error) {
+// queuedAction is an action waiting in the queue and the channels its
+// caller is answered on.
+type queuedAction struct {
+	action     *RecordAction
+	returnChan chan *ZoneRecord
+	errorChan  chan error
+}
+
+// enqueue adds actions to the queue under a single hold of batchMutex, so
+// they are always taken into the same batch.
+func (c *Client) enqueue(queued ...queuedAction) {
 	c.batchMutex.Lock()
 	c.returnChannelsMutex.Lock()
 	defer c.batchMutex.Unlock()
 	defer c.returnChannelsMutex.Unlock()
 
-	c.recordActionQueue = append(c.recordActionQueue, recordAction)
+	for _, q := range queued {
+		recordAction := q.action
 
-	id := c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())
-	c.returnChannels[id] = returnChan
-	c.errorChannels[id] = errorChan
+		// Nothing will flush the queue once the client is stopped, so answer
+		// straight away rather than leave the caller waiting.
+		if c.stopped {
+			select {
+			case q.errorChan <- fmt.Errorf("%w: %s %s in %s was not submitted", ErrClientStopped, recordAction.RecordType, recordAction.KeyId(), recordAction.ZoneName):
+			default:
+			}
+			continue
+		}
 
-	c.triggerFlush()
+		recordAction.errorChan = q.errorChan
+		c.recordActionQueue = append(c.recordActionQueue, recordAction)
+
+		id := c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())
+		c.returnChannels[id] = q.returnChan
+		c.errorChannels[id] = q.errorChan
+	}
+
+	if !c.stopped && !c.Synchronous {
+		c.triggerFlush()
+	}
 }
 
 func (c *Client) flush() error {
 	return c.editZones()
 }
 
+// Flush submits everything queued so far without waiting for the idle timer,
+// returning once the batch has been applied and every caller answered.
+// Unless AsyncWait is set, the error reports any zone whose callers were
+// failed. If ctx ends first, Flush returns its error while the batch carries
+// on.
+func (c *Client) Flush(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		err := c.flush()
+		c.asyncWaits.Wait()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dropAbandoned removes the actions whose callers have given up from a batch
+// about to be submitted, releasing their channels. Callers must hold
+// batchMutex.
+func (c *Client) dropAbandoned(batch []*RecordAction) []*RecordAction {
+	kept := make([]*RecordAction, 0, len(batch))
+	keptIds := make(map[string]bool)
+	var dropped []*RecordAction
+	for _, recordAction := range batch {
+		if recordAction.abandoned() {
+			dropped = append(dropped, recordAction)
+			continue
+		}
+		kept = append(kept, recordAction)
+		keptIds[c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())] = true
+	}
+	if len(dropped) == 0 {
+		return batch
+	}
+
+	c.returnChannelsMutex.Lock()
+	defer c.returnChannelsMutex.Unlock()
+
+	for _, recordAction := range dropped {
+		id := c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())
+		c.logf("dropping %s before it was submitted: %s", id, context.Cause(recordAction.ctx))
+
+		// A later caller for the same record took over the channels.
+		if keptIds[id] {
+			continue
+		}
+		delete(c.returnChannels, id)
+		delete(c.errorChannels, id)
+	}
+
+	return kept
+}
+
+// callersContext returns a context derived from parent that is cancelled
+// once the context of every action has ended, so work nobody is waiting for
+// stops. Actions queued without a context keep it alive. The returned stop
+// function must be called once the work is done.
+func callersContext(parent context.Context, actions []*RecordAction) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	go func() {
+		var last context.Context
+		for _, action := range actions {
+			if action.ctx == nil {
+				return
+			}
+			select {
+			case <-action.ctx.Done():
+				last = action.ctx
+			case <-ctx.Done():
+				return
+			}
+		}
+		if last != nil {
+			cancel(fmt.Errorf("every caller gave up: %w", context.Cause(last)))
+		}
+	}()
+
+	return ctx, func() { cancel(nil) }
+}
+
 func (c *Client) genId(zone string, recordType string, key string, value string) string {
 	return fmt.Sprintf("%s:%s:%s:%s", zone, recordType, key, value)
 }
 
-func (c *Client) clear() {
+// ownsChannels reports whether the channels registered under id are still
+// those of action's caller. Callers must hold returnChannelsMutex.
+func (c *Client) ownsChannels(action *RecordAction, id string) bool {
+	errorChan, ok := c.errorChannels[id]
+	return ok && errorChan == action.errorChan
+}
+
+// clear finishes a batch, closing the channels of any of its callers left
+// unanswered. Actions in requeued go back on the front of the queue with
+// their channels intact.
+func (c *Client) clear(batch []*RecordAction, requeued []*RecordAction) {
 	c.batchMutex.Lock()
 	c.returnChannelsMutex.Lock()
 	defer c.batchMutex.Unlock()
 	defer c.returnChannelsMutex.Unlock()
 
-	// Clear queue
-	c.recordActionQueue = nil
+	kept := make(map[string]bool)
+	for _, recordAction := range requeued {
+		kept[c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())] = true
+	}
+
+	c.recordActionQueue = append(requeued, c.recordActionQueue...)
 
-	// Close pending return channels and clear
-	for _, returnChan := range c.returnChannels {
-		close(returnChan)
+	// Every caller should have been answered by now; anything left would
+	// otherwise only surface as a bare "channel closed" error.
+	var orphaned []string
+	for _, recordAction := range batch {
+		id := c.genId(recordAction.ZoneName, recordAction.RecordType, recordAction.KeyId(), recordAction.ValueId())
+		// Channels re-registered by a caller queued since belong to the
+		// next batch.
+		if kept[id] || !c.ownsChannels(recordAction, id) {
+			continue
+		}
+		orphaned = append(orphaned, id)
+
+		close(c.returnChannels[id])
+		delete(c.returnChannels, id)
+		close(c.errorChannels[id])
+		delete(c.errorChannels, id)
 	}
-	c.returnChannels = make(map[string]chan *ZoneRecord)
 
-	// Close pending error channels and clear
-	for _, errorChan := range c.errorChannels {
-		close(errorChan)
+	sort.Strings(orphaned)
+	c.reportOrphanedChannels("after flush", orphaned)
+}
+
+// abandonQueue marks the client stopped and fails every caller still
+// waiting on a queued action with ErrClientStopped. It waits for any batch
+// in flight to finish first, so actions are either submitted or abandoned,
+// never lost. Actions enqueued afterwards fail immediately.
+func (c *Client) abandonQueue() {
+	c.batchMutex.Lock()
+	c.returnChannelsMutex.Lock()
+	defer c.batchMutex.Unlock()
+	defer c.returnChannelsMutex.Unlock()
+
+	c.stopped = true
+
+	ids := c.orphanedChannelIdsWithoutLock()
+	c.reportOrphanedChannels("at stop", ids)
+
+	for _, id := range ids {
+		if errorChan, ok := c.errorChannels[id]; ok {
+			select {
+			case errorChan <- fmt.Errorf("%w before %s was submitted", ErrClientStopped, id):
+			default:
+			}
+		}
 	}
+
+	c.recordActionQueue = nil
+	c.returnChannels = make(map[string]chan *ZoneRecord)
 	c.errorChannels = make(map[string]chan error)
 }
+
+// orphanedChannelIdsWithoutLock returns the sorted ids of callers still
+// registered for a result. Callers must hold returnChannelsMutex.
+func (c *Client) orphanedChannelIdsWithoutLock() []string {
+	seen := make(map[string]bool)
+	for id := range c.returnChannels {
+		seen[id] = true
+	}
+	for id := range c.errorChannels {
+		seen[id] = true
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (c *Client) reportOrphanedChannels(when string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	c.logf("[WARN] %d orphaned return channel(s) %s: %s", len(ids), when, strings.Join(ids, ", "))
+}
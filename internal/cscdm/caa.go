@@ -0,0 +1,81 @@
+package cscdm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CaaTags are the property tags defined for CAA records by RFC 8659.
+var CaaTags = []string{"issue", "issuewild", "iodef"}
+
+// CaaValue is the structured form of a CAA record value, written as
+// "[<flags>] <tag> <value>" with the value optionally quoted. The flags
+// default to 0 when left out.
+type CaaValue struct {
+	Flags int64
+	Tag   string
+	Value string
+}
+
+// ParseCaaValue splits a CAA record value into its fields, validating the
+// flags and tag.
+func ParseCaaValue(value string) (*CaaValue, error) {
+	fields, err := splitRdata(value)
+	if err != nil {
+		return nil, fmt.Errorf("CAA value %q: %s", value, err)
+	}
+
+	caa := &CaaValue{}
+	switch len(fields) {
+	case 2:
+		caa.Tag, caa.Value = fields[0], fields[1]
+	case 3:
+		flags, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("CAA flags must be a number, got %q", fields[0])
+		}
+		caa.Flags, caa.Tag, caa.Value = flags, fields[1], fields[2]
+	default:
+		return nil, fmt.Errorf("CAA value must be '[<flags>] <tag> <value>', got %q", value)
+	}
+
+	return caa, caa.Validate()
+}
+
+// Validate checks the flags fit in a byte and the tag is a known one.
+func (c *CaaValue) Validate() error {
+	if c.Flags < 0 || c.Flags > 255 {
+		return fmt.Errorf("CAA flags must be between 0 and 255, got %d", c.Flags)
+	}
+
+	for _, tag := range CaaTags {
+		if strings.EqualFold(c.Tag, tag) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("CAA tag must be one of %s, got %q", strings.Join(CaaTags, ", "), c.Tag)
+}
+
+// String assembles the value in the form CSC expects, with the tag in lower
+// case and the value quoted.
+func (c *CaaValue) String() string {
+	return fmt.Sprintf("%d %s %s", c.Flags, strings.ToLower(c.Tag), quoteRdata(c.Value))
+}
+
+// encodeCaaValue returns a CAA value in the form CSC expects, leaving values
+// that do not parse as they are for CSC to reject.
+func encodeCaaValue(value string) string {
+	caa, err := ParseCaaValue(value)
+	if err != nil {
+		return value
+	}
+
+	return caa.String()
+}
+
+// quoteRdata quotes a field so that splitRdata reads it back unchanged.
+func quoteRdata(field string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(field) + `"`
+}
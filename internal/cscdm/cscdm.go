@@ -1,10 +1,16 @@
 package cscdm
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"net/http"
-	"os"
+	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"terraform-provider-cscdm/internal/util"
 	"time"
 
@@ -12,61 +18,634 @@ import (
 )
 
 const (
-	CSC_DOMAIN_MANAGER_API_URL = "https://apis.cscglobal.com/dbs/api/v2/"
+	// CSC_API_VERSION is the CSC Domain Manager API version the client
+	// speaks, as it appears in the API URL path.
+	CSC_API_VERSION            = "v2"
+	CSC_DOMAIN_MANAGER_API_URL = "https://apis.cscglobal.com/dbs/api/" + CSC_API_VERSION + "/"
 	POLL_INTERVAL              = 5 * time.Second
 	FLUSH_IDLE_DURATION        = 5 * time.Second
 	HTTP_REQUEST_TIMEOUT       = 30 * time.Second
+	MAX_BACKOFF                = 30 * time.Second
+	BACKOFF_MULTIPLIER         = 2.0
+	MAX_CONCURRENT_POLLS       = 4
+	MAX_RETRY_ATTEMPTS         = 5
+	OPEN_ZONE_EDITS_ATTEMPTS   = 30
+	MAX_LOG_FILE_SIZE          = 10 << 20
+	OUTAGE_BUFFER_SIZE         = 1000
+	RATE_LIMIT_RETRIES         = 3
+	IDEMPOTENT_RETRIES         = 2
+	IDEMPOTENT_RETRY_DELAY     = 500 * time.Millisecond
+	MAX_RETRY_AFTER            = 2 * time.Minute
+	ZONE_CACHE_TTL             = 60 * time.Second
+	LOST_EDIT_POLLS            = 5
+
+	// EDIT_PATH is the endpoint zone edits are submitted to.
+	EDIT_PATH = "zones/edits"
+	// EDIT_STATUS_PATH is the endpoint a zone edit's status is read from,
+	// with %s standing for the edit id.
+	EDIT_STATUS_PATH = "zones/edits/status/%s"
+	// EDIT_CANCEL_PATH is the endpoint a zone edit is canceled at, with %s
+	// standing for the edit id.
+	EDIT_CANCEL_PATH = "zones/edits/%s"
+
+	// USER_AGENT_PRODUCT names the client in the User-Agent header.
+	USER_AGENT_PRODUCT = "terraform-provider-cscdm"
+
+	// CHANGE_ID_HEADER carries ChangeId on write requests.
+	CHANGE_ID_HEADER = "X-Change-Id"
+
+	// RENAME_STRATEGY_EDIT renames a record with a single EDIT carrying the
+	// new key.
+	RENAME_STRATEGY_EDIT = "edit"
+	// RENAME_STRATEGY_REPLACE renames a record by purging it and adding it
+	// again under the new key in the same batch.
+	RENAME_STRATEGY_REPLACE = "replace"
+	// RENAME_STRATEGY_ERROR refuses to rename records.
+	RENAME_STRATEGY_ERROR = "error"
+
+	// MIN_TTL_ACTION_ERROR rejects record TTLs below MinTtl.
+	MIN_TTL_ACTION_ERROR = "error"
+	// MIN_TTL_ACTION_CLAMP raises record TTLs below MinTtl to MinTtl.
+	MIN_TTL_ACTION_CLAMP = "clamp"
+
+	// PROPAGATION_STATUS_SYNCED is the propagation status CSC reports once
+	// a record is served by all of its nameservers.
+	PROPAGATION_STATUS_SYNCED = "SYNCED"
+
+	// EMPTY_TTL_SERVER_DEFAULT leaves the TTL out of edits for records that
+	// set none, so CSC applies the zone default.
+	EMPTY_TTL_SERVER_DEFAULT = "server_default"
+	// EMPTY_TTL_ZERO sends a TTL of zero for records that set none.
+	EMPTY_TTL_ZERO = "zero"
+
+	// HOSTING_TYPE_ACTION_WARN warns about records in zones whose hosting
+	// type is not in RecordHostingTypes.
+	HOSTING_TYPE_ACTION_WARN = "warn"
+	// HOSTING_TYPE_ACTION_ERROR rejects records in zones whose hosting type
+	// is not in RecordHostingTypes.
+	HOSTING_TYPE_ACTION_ERROR = "error"
 )
 
 type Client struct {
-	http *http.Client
+	// BaseUrl is the CSC Domain Manager API root. Defaults to
+	// CSC_DOMAIN_MANAGER_API_URL when unset.
+	BaseUrl string
+	// PollInterval is the initial delay between zone edit status polls and
+	// OPEN_ZONE_EDITS retries. Defaults to POLL_INTERVAL when unset.
+	PollInterval time.Duration
+	// MaxBackoff caps the delay between retries and polls, which otherwise
+	// grows from PollInterval by BackoffMultiplier on each attempt. Each
+	// delay is then jittered down by up to half so clients retrying together
+	// spread out. Defaults to MAX_BACKOFF when unset.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor the delay between retries and polls
+	// grows by on each attempt. Defaults to BACKOFF_MULTIPLIER when unset.
+	BackoffMultiplier float64
+	// RequestTimeout bounds each request to CSC, including reading its
+	// response, so a connection CSC accepts but never answers cannot hang a
+	// call. It applies to every attempt the transport makes on its own, and
+	// not to the waits between them, such as a Retry-After of up to
+	// MAX_RETRY_AFTER. A timed-out request is handled like any other that
+	// got no response, subject to RetryPolicy. Defaults to
+	// HTTP_REQUEST_TIMEOUT when unset.
+	RequestTimeout time.Duration
+	// FlushIdleDuration is how often the queue is flushed while nothing is
+	// being enqueued, which picks up batches re-queued after a zone lock
+	// conflict. Defaults to FLUSH_IDLE_DURATION when unset.
+	FlushIdleDuration time.Duration
+	// FlushGracePeriod is how long the flush loop waits after the last
+	// enqueue for further actions to join the batch before flushing it.
+	// Shorter periods lower latency, longer ones gather bigger batches.
+	// Defaults to FlushIdleDuration when unset.
+	FlushGracePeriod time.Duration
+	// RenameStrategy controls how an EDIT that changes a record's key is
+	// carried out. Defaults to RENAME_STRATEGY_EDIT when unset.
+	RenameStrategy string
+	// EmptyTtl is what callers should send for a record that sets no TTL:
+	// nothing under EMPTY_TTL_SERVER_DEFAULT, or an explicit zero, see
+	// ZoneEdit.ZeroTtl, under EMPTY_TTL_ZERO. Defaults to
+	// EMPTY_TTL_SERVER_DEFAULT when unset.
+	EmptyTtl string
+	// MinTlsVersion is the oldest TLS version the client will negotiate.
+	// Defaults to TLS 1.2 when unset.
+	MinTlsVersion uint16
+	// ProxyUrl routes every request through a proxy, sending any credentials
+	// it holds as Proxy-Authorization. When nil, the standard HTTPS_PROXY,
+	// HTTP_PROXY and NO_PROXY environment variables apply.
+	ProxyUrl *url.URL
+	// RootCAs is the certificate pool that CSC's certificate is verified
+	// against, such as one trusting a private CA in front of the API. Nil
+	// uses the system pool.
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify disables certificate verification entirely. It is
+	// meant for testing against servers with self-signed certificates only.
+	InsecureSkipVerify bool
+	// DependencyChecks makes PerformRecordPurge report CNAME and MX records
+	// left pointing at a name that their batch removed.
+	DependencyChecks bool
+	// MinTtl is the lowest record TTL, in seconds, that callers should
+	// accept. Zero disables the check.
+	MinTtl int64
+	// MinTtlAction is what callers should do with a TTL below MinTtl.
+	// Defaults to MIN_TTL_ACTION_ERROR when unset.
+	MinTtlAction string
+	// Synchronous disables batching: no flush loop is started and each
+	// PerformRecordAction submits its edit immediately and waits for it.
+	// Stop is a no-op for synchronous clients.
+	Synchronous bool
+	// EditPreviewPath, when set, is a file that every submitted zone edit
+	// request is appended to as a line of JSON, for auditing.
+	EditPreviewPath string
+	// MaxConcurrentPolls bounds how many zone edit status requests may be in
+	// flight at once. Defaults to MAX_CONCURRENT_POLLS when unset.
+	MaxConcurrentPolls int
+	// ZoneDefaults holds per-zone values for records that omit them, keyed
+	// by zone name.
+	ZoneDefaults map[string]ZoneDefaults
+	// RetryPolicy decides which failed requests to zone edit, status and zone
+	// endpoints are retried, apart from GETs failing with a 5xx, which are
+	// retried by the transport as set by IdempotentRetries. When unset, a zone edit is submitted up to
+	// OpenZoneEditsAttempts times while CSC rejects it with OPEN_ZONE_EDITS,
+	// and 429 responses are retried up to MAX_RETRY_ATTEMPTS times, backing
+	// off from PollInterval up to MaxBackoff. A 5xx answer to a zone edit
+	// wraps ErrOutcomeUnknown, since the edit may have been applied; a policy
+	// retrying it risks applying the edit twice.
+	RetryPolicy RetryPolicy
+	// RateLimitRetries is how many times a request CSC rate limits with a
+	// Retry-After header is sent again, after waiting as the header says, up
+	// to MAX_RETRY_AFTER, before the 429 is handed to RetryPolicy. Defaults
+	// to RATE_LIMIT_RETRIES when unset; a negative value disables it.
+	RateLimitRetries int
+	// IdempotentRetries is how many times a GET that fails with a 5xx
+	// status, a timeout or a reset connection is sent again by the
+	// transport, backing off from IDEMPOTENT_RETRY_DELAY up to MaxBackoff.
+	// A GET still failing with a 5xx afterwards is not handed to
+	// RetryPolicy, so it is retried in one place only. Writes are never
+	// retried this way. Defaults to IDEMPOTENT_RETRIES when unset; a
+	// negative value disables it.
+	IdempotentRetries int
+	// OpenZoneEditsAttempts is how many times the default RetryPolicy
+	// submits a zone edit while CSC reports OPEN_ZONE_EDITS before giving
+	// up. Defaults to OPEN_ZONE_EDITS_ATTEMPTS when unset.
+	OpenZoneEditsAttempts int
+	// EditPath, EditStatusPath and EditCancelPath override the zone edit
+	// endpoints, relative to BaseUrl. The status and cancel paths must hold a
+	// single %s for the edit id; see ValidatePathTemplate. Default to
+	// EDIT_PATH, EDIT_STATUS_PATH and EDIT_CANCEL_PATH.
+	EditPath       string
+	EditStatusPath string
+	EditCancelPath string
+	// EditValidatePath is the endpoint ValidateZoneEdits submits edits to
+	// for checking without applying them. Edits are only checked locally
+	// while it is unset.
+	EditValidatePath string
+	// ZoneLockRequeues is how many times a zone's batch may be put back on
+	// the queue for a later flush when CSC still reports OPEN_ZONE_EDITS
+	// after RetryPolicy gives up, before its callers are failed. Zero, the
+	// default, fails them straight away. Synchronous clients never re-queue.
+	ZoneLockRequeues int
+	// LogFile, when set, is a file the client's log lines are mirrored to
+	// in addition to stderr, with credentials redacted.
+	LogFile string
+	// FailFast stops a batch as soon as one zone fails: zones not yet
+	// submitted are skipped and their callers fail too. Zones already
+	// submitted are still waited on, since CSC goes on to apply them. By
+	// default every zone runs to completion.
+	FailFast bool
+	// StatusCallback, when set, is called with a zone edit's id and status
+	// each time its status is polled, for reporting progress on long-running
+	// edits. It may be called concurrently for edits to different zones.
+	StatusCallback func(editId string, status string)
+	// ZoneLocker, when set, is held for each zone while its edits are
+	// submitted and applied, serializing them with other clients sharing
+	// it. See FileLocker for a lock shared between processes on one host.
+	ZoneLocker Locker
+	// RecordHostingTypes, when set, lists the zone hosting types records
+	// may be managed in; see CheckHostingType. HostingTypeAction says
+	// whether other zones are warned about or rejected, and defaults to
+	// HOSTING_TYPE_ACTION_WARN.
+	RecordHostingTypes []string
+	HostingTypeAction  string
+	// AsyncWait lets a batch release the queue as soon as its edits are
+	// submitted, so the next batch is submitted while CSC is still applying
+	// the previous one instead of waiting behind it. Callers are still
+	// answered only once their own zone's edits complete. By default each
+	// batch is applied in full before the next is submitted.
+	AsyncWait bool
+	// ValueTransform rewrites record values on their way to and from CSC,
+	// for conventions such as environment tags that should not appear in
+	// configuration. Defaults to IdentityTransform.
+	ValueTransform ValueTransform
+	// UserAgent is sent as the User-Agent header of every request, so CSC
+	// can tell which client made it. Defaults to UserAgent("dev") when unset.
+	UserAgent string
+	// ChangeId, when set, is sent in the CHANGE_ID_HEADER header of every
+	// request that changes a zone, so CSC's audit log ties the edits to a
+	// change ticket.
+	ChangeId string
+	// ProtectLastMx refuses to remove the last MX record at a name, which
+	// would stop mail for it being delivered, unless the removing action
+	// sets AllowLastRecord.
+	ProtectLastMx bool
+	// ValidateEdits asks callers to check planned record changes with
+	// ValidateZoneEdits before applying them. The client itself does not
+	// act on it.
+	ValidateEdits bool
+	// OutageWindow, when set, holds the edits of a zone that could not be
+	// submitted because CSC was unreachable, retrying them on later flushes
+	// until CSC answers or the window since the first failure passes, when
+	// they fail with ErrOutageWindowExceeded. Only edits that never reached
+	// CSC are held. Synchronous clients never hold edits.
+	OutageWindow time.Duration
+	// OutageBufferSize bounds how many actions may be held at once under
+	// OutageWindow; beyond it, a zone's edits fail straight away. Defaults
+	// to OUTAGE_BUFFER_SIZE when unset.
+	OutageBufferSize int
+	// ZoneCacheTtl is how long a zone read from CSC is reused before it is
+	// read again, so a long-running process picks up changes made outside
+	// of it. Edits made through the client drop the zone straight away.
+	// Defaults to ZONE_CACHE_TTL when unset.
+	ZoneCacheTtl time.Duration
+	// DisableCache makes every zone read go to CSC, for debugging against
+	// the live API. Concurrent reads of the same zone still share a single
+	// request.
+	DisableCache bool
+
+	http     *http.Client
+	apiKey   string
+	apiToken string
+
+	scopedClients map[string]*Client
+	scopedMutex   sync.Mutex
 
 	recordActionQueue   []*RecordAction
+	stopped             bool
+	asyncWaits          sync.WaitGroup
 	returnChannels      map[string]chan *ZoneRecord
 	errorChannels       map[string]chan error
 	batchMutex          sync.Mutex
 	returnChannelsMutex sync.Mutex
 
+	pollSemaphore chan struct{}
+
 	flushTrigger      chan struct{}
 	flushLoopStopChan chan struct{}
+	flushLoopDone     chan struct{}
+	configureOnce     sync.Once
 	stopOnce          sync.Once
 
-	zoneCache  map[string]*Zone
-	zoneGroup  singleflight.Group
-	cacheMutex sync.RWMutex
+	orphans      map[string][]string
+	orphansMutex sync.Mutex
+
+	soaClaims      map[string]bool
+	soaClaimsMutex sync.Mutex
+
+	zoneCache       map[string]cachedZone
+	zoneRecordCache map[string]map[string]cachedRecords
+	zoneGroup       singleflight.Group
+	cacheMutex      sync.RWMutex
+
+	recordTypeFilterUnsupported atomic.Bool
+	editValidationUnsupported   atomic.Bool
 }
 
+// Configure prepares the client and starts its background flush loop. Only
+// the first call has any effect; re-configuring requires a fresh Client.
 func (c *Client) Configure(apiKey string, apiToken string) {
+	c.configureOnce.Do(func() {
+		c.configure(apiKey, apiToken)
+	})
+}
+
+func (c *Client) configure(apiKey string, apiToken string) {
+	if c.BaseUrl == "" {
+		c.BaseUrl = CSC_DOMAIN_MANAGER_API_URL
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = POLL_INTERVAL
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = MAX_BACKOFF
+	}
+	if c.BackoffMultiplier == 0 {
+		c.BackoffMultiplier = BACKOFF_MULTIPLIER
+	}
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = HTTP_REQUEST_TIMEOUT
+	}
+	if c.FlushIdleDuration == 0 {
+		c.FlushIdleDuration = FLUSH_IDLE_DURATION
+	}
+	if c.FlushGracePeriod == 0 {
+		c.FlushGracePeriod = c.FlushIdleDuration
+	}
+	if c.RenameStrategy == "" {
+		c.RenameStrategy = RENAME_STRATEGY_EDIT
+	}
+	if c.OpenZoneEditsAttempts == 0 {
+		c.OpenZoneEditsAttempts = OPEN_ZONE_EDITS_ATTEMPTS
+	}
+	if c.MaxConcurrentPolls == 0 {
+		c.MaxConcurrentPolls = MAX_CONCURRENT_POLLS
+	}
+	if c.RateLimitRetries == 0 {
+		c.RateLimitRetries = RATE_LIMIT_RETRIES
+	}
+	if c.IdempotentRetries == 0 {
+		c.IdempotentRetries = IDEMPOTENT_RETRIES
+	}
+	if c.EmptyTtl == "" {
+		c.EmptyTtl = EMPTY_TTL_SERVER_DEFAULT
+	}
+	if c.MinTtlAction == "" {
+		c.MinTtlAction = MIN_TTL_ACTION_ERROR
+	}
+	if c.HostingTypeAction == "" {
+		c.HostingTypeAction = HOSTING_TYPE_ACTION_WARN
+	}
+	if c.EditPath == "" {
+		c.EditPath = EDIT_PATH
+	}
+	if c.EditStatusPath == "" {
+		c.EditStatusPath = EDIT_STATUS_PATH
+	}
+	if c.EditCancelPath == "" {
+		c.EditCancelPath = EDIT_CANCEL_PATH
+	}
+	if c.OutageBufferSize == 0 {
+		c.OutageBufferSize = OUTAGE_BUFFER_SIZE
+	}
+	if c.MinTlsVersion == 0 {
+		c.MinTlsVersion = tls.VersionTLS12
+	}
+	if c.ValueTransform == nil {
+		c.ValueTransform = IdentityTransform{}
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = UserAgent("dev")
+	}
+	if c.ZoneCacheTtl == 0 {
+		c.ZoneCacheTtl = ZONE_CACHE_TTL
+	}
+
+	c.apiKey = apiKey
+	c.apiToken = apiToken
+
+	if err := CheckApiVersion(c.BaseUrl); err != nil {
+		c.logf("[WARN] %s", err.Error())
+	}
+
+	baseTransport := util.NewBaseTransport(c.MinTlsVersion)
+	if c.ProxyUrl != nil {
+		baseTransport.Proxy = http.ProxyURL(c.ProxyUrl)
+	}
+	baseTransport.TLSClientConfig.RootCAs = c.RootCAs
+	baseTransport.TLSClientConfig.InsecureSkipVerify = c.InsecureSkipVerify
+
 	c.http = &http.Client{
-		Timeout: HTTP_REQUEST_TIMEOUT,
 		Transport: &util.HttpTransport{
-			BaseUrl: CSC_DOMAIN_MANAGER_API_URL,
+			BaseTransport: baseTransport,
+			BaseUrl:       c.BaseUrl,
 			Headers: map[string]string{
 				"accept":        "application/json",
 				"apikey":        apiKey,
 				"Authorization": fmt.Sprintf("Bearer %s", apiToken),
+				"User-Agent":    c.UserAgent,
 			},
+			MaxRateLimitRetries: max(c.RateLimitRetries, 0),
+			MaxRetryAfter:       MAX_RETRY_AFTER,
+
+			MaxIdempotentRetries: max(c.IdempotentRetries, 0),
+			RetryDelay:           IDEMPOTENT_RETRY_DELAY,
+			MaxRetryDelay:        c.MaxBackoff,
+
+			AttemptTimeout: c.RequestTimeout,
 		}}
 
 	c.returnChannels = make(map[string]chan *ZoneRecord)
 	c.errorChannels = make(map[string]chan error)
 
+	c.pollSemaphore = make(chan struct{}, c.MaxConcurrentPolls)
+
 	c.flushTrigger = make(chan struct{}, 1)
 	c.flushLoopStopChan = make(chan struct{})
+	c.flushLoopDone = make(chan struct{})
+
+	c.orphans = make(map[string][]string)
+	c.soaClaims = make(map[string]bool)
+
+	c.zoneCache = make(map[string]cachedZone)
+	c.zoneRecordCache = make(map[string]map[string]cachedRecords)
+	c.scopedClients = make(map[string]*Client)
 
-	c.zoneCache = make(map[string]*Zone)
+	if c.Synchronous {
+		close(c.flushLoopDone)
+		return
+	}
 
 	go c.flushLoop()
 }
 
+// WithCredentials returns a client sharing this client's options but
+// authenticating with different credentials, for zones owned by another CSC
+// account. Clients are cached per credential pair so each pair gets a single
+// batching queue and flush loop; they are stopped along with this client.
+func (c *Client) WithCredentials(apiKey string, apiToken string) *Client {
+	if apiKey == c.apiKey && apiToken == c.apiToken {
+		return c
+	}
+
+	sum := sha256.Sum256([]byte(apiKey + "\x00" + apiToken))
+	id := hex.EncodeToString(sum[:])
+
+	c.scopedMutex.Lock()
+	defer c.scopedMutex.Unlock()
+
+	if scoped, ok := c.scopedClients[id]; ok {
+		return scoped
+	}
+
+	scoped := c.cloneOptions()
+	scoped.Configure(apiKey, apiToken)
+	c.scopedClients[id] = scoped
+
+	return scoped
+}
+
+// cloneOptions returns an unconfigured client with the same exported
+// options as c. Every exported option field must be copied here.
+func (c *Client) cloneOptions() *Client {
+	return &Client{
+		BaseUrl:            c.BaseUrl,
+		PollInterval:       c.PollInterval,
+		MaxBackoff:         c.MaxBackoff,
+		BackoffMultiplier:  c.BackoffMultiplier,
+		RequestTimeout:     c.RequestTimeout,
+		FlushIdleDuration:  c.FlushIdleDuration,
+		FlushGracePeriod:   c.FlushGracePeriod,
+		RenameStrategy:     c.RenameStrategy,
+		MinTlsVersion:      c.MinTlsVersion,
+		ProxyUrl:           c.ProxyUrl,
+		RootCAs:            c.RootCAs,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		DependencyChecks:   c.DependencyChecks,
+		MinTtl:             c.MinTtl,
+		MinTtlAction:       c.MinTtlAction,
+		EmptyTtl:           c.EmptyTtl,
+		Synchronous:        c.Synchronous,
+		EditPreviewPath:    c.EditPreviewPath,
+		MaxConcurrentPolls: c.MaxConcurrentPolls,
+		ZoneDefaults:       c.ZoneDefaults,
+		RetryPolicy:        c.RetryPolicy,
+		EditPath:           c.EditPath,
+		EditStatusPath:     c.EditStatusPath,
+		EditCancelPath:     c.EditCancelPath,
+		EditValidatePath:   c.EditValidatePath,
+		ZoneLockRequeues:   c.ZoneLockRequeues,
+		LogFile:            c.LogFile,
+		FailFast:           c.FailFast,
+		StatusCallback:     c.StatusCallback,
+		ZoneLocker:         c.ZoneLocker,
+		RecordHostingTypes: c.RecordHostingTypes,
+		HostingTypeAction:  c.HostingTypeAction,
+		AsyncWait:          c.AsyncWait,
+		ValueTransform:     c.ValueTransform,
+		ChangeId:           c.ChangeId,
+		ProtectLastMx:      c.ProtectLastMx,
+		ValidateEdits:      c.ValidateEdits,
+
+		OutageWindow:          c.OutageWindow,
+		OutageBufferSize:      c.OutageBufferSize,
+		OpenZoneEditsAttempts: c.OpenZoneEditsAttempts,
+		RateLimitRetries:      c.RateLimitRetries,
+		IdempotentRetries:     c.IdempotentRetries,
+		UserAgent:             c.UserAgent,
+		ZoneCacheTtl:          c.ZoneCacheTtl,
+		DisableCache:          c.DisableCache,
+	}
+}
+
+// UserAgent returns the User-Agent for a provider version, such as
+// "terraform-provider-cscdm/1.2.0 (+terraform)".
+func UserAgent(version string) string {
+	return fmt.Sprintf("%s/%s (+terraform)", USER_AGENT_PRODUCT, version)
+}
+
+// ApiVersion returns the API version segment of a CSC API URL, such as "v2",
+// or an empty string if its path has none.
+func ApiVersion(apiUrl string) string {
+	parsed, err := url.Parse(apiUrl)
+	if err != nil {
+		return ""
+	}
+
+	version := ""
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if len(segment) > 1 && segment[0] == 'v' && strings.Trim(segment[1:], "0123456789") == "" {
+			version = segment
+		}
+	}
+
+	return version
+}
+
+// CheckApiVersion reports an API URL whose version differs from
+// CSC_API_VERSION, which the client's requests and responses are built for.
+// URLs without a version, such as a proxy that rewrites paths, pass.
+func CheckApiVersion(apiUrl string) error {
+	version := ApiVersion(apiUrl)
+	if version == "" || version == CSC_API_VERSION {
+		return nil
+	}
+
+	return fmt.Errorf("API URL %s targets CSC API version %s, but this provider supports %s; requests may fail or be misread", apiUrl, version, CSC_API_VERSION)
+}
+
+// NormalizeApiUrl checks that an API URL override is an absolute http or
+// https URL and returns it with the trailing slash that relative endpoint
+// paths are resolved against.
+func NormalizeApiUrl(apiUrl string) (string, error) {
+	parsed, err := url.Parse(apiUrl)
+	if err != nil {
+		return "", fmt.Errorf("API URL %q is not a valid URL: %s", apiUrl, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("API URL %q must be an absolute http or https URL", apiUrl)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("API URL %q must not contain a query or fragment", apiUrl)
+	}
+
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+		if parsed.RawPath != "" {
+			parsed.RawPath += "/"
+		}
+	}
+
+	return parsed.String(), nil
+}
+
+// ValidatePathTemplate checks an endpoint path override. Templates taking an
+// id must contain exactly one %s and no other formatting verbs; others must
+// contain none.
+func ValidatePathTemplate(template string, takesId bool) error {
+	if template == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+
+	verbs := strings.Count(template, "%")
+	ids := strings.Count(template, "%s")
+
+	if takesId && (ids != 1 || verbs != 1) {
+		return fmt.Errorf("path %q must contain exactly one %%s for the edit id and no other %% characters", template)
+	}
+	if !takesId && verbs != 0 {
+		return fmt.Errorf("path %q must not contain %% characters", template)
+	}
+
+	return nil
+}
+
+// ClampTtl returns the TTL to send for a configured TTL, raising it to MinTtl
+// when MinTtlAction is MIN_TTL_ACTION_CLAMP. An unset (zero) TTL is left
+// alone.
+func (c *Client) ClampTtl(ttl int64) int64 {
+	if ttl == 0 || ttl >= c.MinTtl || c.MinTtlAction != MIN_TTL_ACTION_CLAMP {
+		return ttl
+	}
+
+	return c.MinTtl
+}
+
 func (c *Client) flushLoop() {
+	defer close(c.flushLoopDone)
+
+	// pending is set by an enqueue and cleared by the flush it leads to.
+	// While set, the loop waits out the grace period instead of the idle
+	// interval.
+	pending := false
+
+	// failures counts consecutive failed flushes. While non-zero, the next
+	// flush is put off by a backoff from FlushIdleDuration up to MaxBackoff
+	// so a persistent failure does not hammer CSC.
+	failures := 0
+
 	for {
-		flushTimer := time.NewTimer(FLUSH_IDLE_DURATION)
+		wait := c.FlushIdleDuration
+		if pending {
+			wait = c.FlushGracePeriod
+		}
+		if failures > 0 {
+			wait = max(wait, Backoff(c.FlushIdleDuration, c.MaxBackoff, failures))
+		}
+		flushTimer := time.NewTimer(wait)
 
 		select {
 		case <-c.flushTrigger:
 			// Flush triggered; reset flush timer
 			flushTimer.Stop()
+			pending = true
 			// Drain the channel in case of multiple signals
 			select {
 			case <-c.flushTrigger:
@@ -74,11 +653,15 @@ func (c *Client) flushLoop() {
 			}
 		case <-flushTimer.C:
 			// Timer expired; flush queue
+			pending = false
 			err := c.flush()
 
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "failed to flush queue: %s\n", err.Error())
+				failures++
+				c.logf("failed to flush queue, backing off for %s: %s", Backoff(c.FlushIdleDuration, c.MaxBackoff, failures), err.Error())
 				// Continue - don't return/terminate
+			} else {
+				failures = 0
 			}
 		case <-c.flushLoopStopChan:
 			// Stop flush loop
@@ -96,8 +679,21 @@ func (c *Client) triggerFlush() {
 	}
 }
 
+// Stop terminates the flush loop, waiting for it to exit, along with those
+// of any clients returned by WithCredentials.
 func (c *Client) Stop() {
 	c.stopOnce.Do(func() {
 		close(c.flushLoopStopChan)
+		<-c.flushLoopDone
+		c.asyncWaits.Wait()
+
+		c.abandonQueue()
 	})
+
+	c.scopedMutex.Lock()
+	defer c.scopedMutex.Unlock()
+
+	for _, scoped := range c.scopedClients {
+		scoped.Stop()
+	}
 }
@@ -0,0 +1,277 @@
+package cscdm_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/util"
+	"testing"
+	"time"
+)
+
+func TestBackoff_NeverExceedsCap(t *testing.T) {
+	base := 5 * time.Second
+	limit := 30 * time.Second
+
+	previous := time.Duration(0)
+	for attempt := 0; attempt < 200; attempt++ {
+		delay := cscdm.Backoff(base, limit, attempt)
+
+		if delay > limit {
+			t.Fatalf("Attempt %d: delay %s exceeds cap %s", attempt, delay, limit)
+		}
+		if delay < previous {
+			t.Fatalf("Attempt %d: delay %s shrank from %s", attempt, delay, previous)
+		}
+		previous = delay
+	}
+
+	if delay := cscdm.Backoff(base, limit, 0); delay != base {
+		t.Errorf("Expected first delay %s, got %s", base, delay)
+	}
+	if delay := cscdm.Backoff(base, limit, 1); delay != 2*base {
+		t.Errorf("Expected second delay %s, got %s", 2*base, delay)
+	}
+	if delay := cscdm.Backoff(time.Minute, limit, 0); delay != limit {
+		t.Errorf("Expected base above cap to be clamped to %s, got %s", limit, delay)
+	}
+}
+
+func TestScaledBackoff_UsesMultiplier(t *testing.T) {
+	base := time.Second
+	limit := time.Minute
+
+	if delay := cscdm.ScaledBackoff(base, limit, 3, 2); delay != 9*base {
+		t.Errorf("Expected third delay %s, got %s", 9*base, delay)
+	}
+	if delay := cscdm.ScaledBackoff(base, limit, 1.5, 1); delay != 1500*time.Millisecond {
+		t.Errorf("Expected second delay 1.5s, got %s", delay)
+	}
+	if delay := cscdm.ScaledBackoff(base, limit, 0.5, 10); delay != base {
+		t.Errorf("Expected a multiplier below 1 to keep the delay at %s, got %s", base, delay)
+	}
+	if delay := cscdm.ScaledBackoff(base, limit, 10, 5); delay != limit {
+		t.Errorf("Expected delay to be capped at %s, got %s", limit, delay)
+	}
+}
+
+func TestJitter_StaysWithinHalfOfDelay(t *testing.T) {
+	d := 100 * time.Millisecond
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 1000; i++ {
+		delay := cscdm.Jitter(d)
+		if delay < d/2 || delay > d {
+			t.Fatalf("Expected jittered delay within [%s, %s], got %s", d/2, d, delay)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected jitter to vary the delay")
+	}
+}
+
+func TestClient_FlushLoopBacksOffAfterFailures(t *testing.T) {
+	const failures = 3
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	// The first submissions are rejected, failing the flushes that sent
+	// them.
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	var mu sync.Mutex
+	var calls []time.Time
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		mu.Lock()
+		calls = append(calls, time.Now())
+		n := len(calls)
+		mu.Unlock()
+
+		if n <= failures {
+			writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "INVALID_RECORD", Description: "record is invalid"})
+			return true
+		}
+		return false
+	}
+
+	idle := 10 * time.Millisecond
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      time.Millisecond,
+		FlushIdleDuration: idle,
+		MaxBackoff:        80 * time.Millisecond,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	// Each action is queued once the last has been answered, so every flush
+	// has something to submit.
+	for i := 0; i < failures+2; i++ {
+		_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: fmt.Sprintf("www%d", i), NewValue: "10.0.0.1"},
+		})
+		if (err != nil) != (i < failures) {
+			t.Fatalf("Action %d: expected failure = %t, got: %v", i, i < failures, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Each failure doubles the wait before the next flush.
+	for i := 1; i <= failures; i++ {
+		want := cscdm.Backoff(idle, client.MaxBackoff, i)
+		if gap := calls[i].Sub(calls[i-1]); gap < want {
+			t.Errorf("Expected flush %d to wait at least %s after failure %d, waited %s", i+1, want, i, gap)
+		}
+	}
+
+	// A successful flush resets the wait to the idle interval.
+	if gap := calls[failures+1].Sub(calls[failures]); gap >= cscdm.Backoff(idle, client.MaxBackoff, failures) {
+		t.Errorf("Expected the wait to reset after a successful flush, waited %s", gap)
+	}
+}
+
+func TestClient_OpenZoneEditsAttemptsAreCapped(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "OPEN_ZONE_EDITS", Description: "zone has open edits"})
+		return true
+	}
+
+	client := &cscdm.Client{
+		BaseUrl:               fake.URL + "/",
+		PollInterval:          time.Millisecond,
+		Synchronous:           true,
+		OpenZoneEditsAttempts: 3,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts over") || !strings.Contains(err.Error(), "last error OPEN_ZONE_EDITS") {
+		t.Fatalf("Expected the error to give the attempts made and the last error code, got %v", err)
+	}
+	if n := len(fake.submittedEdits()); n != 3 {
+		t.Errorf("Expected 3 submissions, got %d", n)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{"Fri, 01 Mar 2024 10:00:30 GMT", 30 * time.Second, true},
+		{"Fri, 01 Mar 2024 09:00:00 GMT", 0, true},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+
+	for _, test := range tests {
+		delay, ok := util.ParseRetryAfter(test.value, now)
+		if delay != test.delay || ok != test.ok {
+			t.Errorf("ParseRetryAfter(%q) = %s, %t; expected %s, %t", test.value, delay, ok, test.delay, test.ok)
+		}
+	}
+}
+
+func TestClient_HonorsRetryAfterOnRateLimit(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	var limited int
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		if limited < 2 {
+			limited++
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return true
+		}
+		return false
+	}
+
+	var retries int
+	client := &cscdm.Client{
+		BaseUrl:     fake.URL + "/",
+		Synchronous: true,
+		RetryPolicy: func(attempt int, statusCode int, err error) (bool, time.Duration) {
+			retries++
+			return false, 0
+		},
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the rate limited edit to go through, got %s", err)
+	}
+	if n := len(fake.submittedEdits()); n != 3 || retries != 0 {
+		t.Errorf("Expected the transport to resend the edit twice without the retry policy, got %d submissions and %d policy retries", n, retries)
+	}
+
+	// With the retries disabled the first 429 reaches the retry policy.
+	limited = 0
+	disabled := &cscdm.Client{
+		BaseUrl:          fake.URL + "/",
+		Synchronous:      true,
+		RateLimitRetries: -1,
+		RetryPolicy:      client.RetryPolicy,
+	}
+	disabled.Configure("test-key", "test-token")
+	t.Cleanup(disabled.Stop)
+
+	_, err = disabled.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "api", NewValue: "10.0.0.2"},
+	})
+	if err == nil || retries != 1 {
+		t.Errorf("Expected the 429 to be returned to the retry policy, got %v after %d policy calls", err, retries)
+	}
+}
+
+func TestClient_ZoneReadServerErrorsRetriedOnce(t *testing.T) {
+	var requests atomic.Int64
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(fake.Close)
+
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		Synchronous:       true,
+		IdempotentRetries: 2,
+		MaxBackoff:        time.Millisecond,
+		RetryPolicy: func(int, int, error) (bool, time.Duration) {
+			return true, time.Millisecond
+		},
+	}
+	client.Configure("test-key", "test-token")
+
+	if _, err := client.FetchZone(context.Background(), "example.com"); err == nil {
+		t.Fatalf("Expected the zone read to fail")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected only the transport's 2 retries, got %d requests", n)
+	}
+}
@@ -0,0 +1,896 @@
+package cscdm_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+func TestClient_MixedActionsCoalescedIntoOneZoneEdit(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A: []cscdm.ZoneRecord{
+			{Id: "1", Key: "old", Value: "10.0.0.1"},
+			{Id: "2", Key: "www", Value: "10.0.0.2"},
+		},
+	})
+	client := fake.newClient(t)
+
+	actions := []*cscdm.RecordAction{
+		{ZoneName: "example.com", ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "new", NewValue: "10.0.0.3"}},
+		{ZoneName: "example.com", ZoneEdit: cscdm.ZoneEdit{Action: "EDIT", RecordType: "A", CurrentKey: "www", CurrentValue: "10.0.0.2", NewKey: "www", NewValue: "10.0.0.4"}},
+		{ZoneName: "example.com", ZoneEdit: cscdm.ZoneEdit{Action: "PURGE", RecordType: "A", CurrentKey: "old", CurrentValue: "10.0.0.1"}},
+	}
+
+	var wg sync.WaitGroup
+	for _, action := range actions {
+		wg.Add(1)
+		go func(action *cscdm.RecordAction) {
+			defer wg.Done()
+
+			if _, err := client.PerformRecordAction(context.Background(), action); err != nil {
+				t.Errorf("%s failed: %s", action.Action, err)
+			}
+		}(action)
+	}
+	wg.Wait()
+
+	submitted := fake.submittedEdits()
+	if len(submitted) != 1 {
+		t.Fatalf("Expected 1 zone edit request, got %d", len(submitted))
+	}
+
+	var order []string
+	for _, edit := range submitted[0].Edits {
+		order = append(order, edit.Action)
+	}
+
+	expected := []string{"PURGE", "EDIT", "ADD"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected edits %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected edits %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestClient_FlushWithEmptyQueueIsNoop(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	if err := client.Flush(context.Background()); err != nil {
+		t.Fatalf("Expected an empty flush to succeed, got %s", err)
+	}
+
+	if n := fake.requestCount(); n != 0 {
+		t.Errorf("Expected no requests from empty flushes, got %d", n)
+	}
+}
+
+func TestJoinBatchErrors_StableOrder(t *testing.T) {
+	var zoneErrs []error
+	for _, zone := range []string{"c.example", "a.example", "d.example", "b.example"} {
+		zoneErrs = append(zoneErrs, fmt.Errorf("failed to return error: failed to get error channel for %s:A:www:10.0.0.1", zone))
+	}
+
+	expected := cscdm.JoinBatchErrors(zoneErrs).Error()
+
+	// Every rotation of the failures stands in for a different completion
+	// order of the zone goroutines.
+	for i := range zoneErrs {
+		rotated := append(append([]error(nil), zoneErrs[i:]...), zoneErrs[:i]...)
+
+		if got := cscdm.JoinBatchErrors(rotated).Error(); got != expected {
+			t.Errorf("Expected %q regardless of order, got %q", expected, got)
+		}
+	}
+
+	if err := cscdm.JoinBatchErrors(nil); err != nil {
+		t.Errorf("Expected no error for an empty batch, got %v", err)
+	}
+
+	if !strings.HasPrefix(expected, "4 error(s)") || strings.Index(expected, "a.example") > strings.Index(expected, "b.example") {
+		t.Errorf("Expected 4 errors sorted by message, got %q", expected)
+	}
+}
+
+func TestClient_EditPreviewRecordsSubmittedEdits(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "a.example"}, &cscdm.Zone{ZoneName: "b.example"})
+	client := fake.newClient(t)
+	client.EditPreviewPath = filepath.Join(t.TempDir(), "edits.jsonl")
+
+	var wg sync.WaitGroup
+	for _, zone := range []string{"a.example", "b.example"} {
+		wg.Add(1)
+		go func(zone string) {
+			defer wg.Done()
+
+			_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+				ZoneName: zone,
+				ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+			})
+			if err != nil {
+				t.Errorf("Add to %s failed: %s", zone, err)
+			}
+		}(zone)
+	}
+	wg.Wait()
+
+	f, err := os.Open(client.EditPreviewPath)
+	if err != nil {
+		t.Fatalf("Failed to open edit preview: %s", err)
+	}
+	defer f.Close()
+
+	zones := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var req cscdm.ZoneEditReq
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			t.Fatalf("Edit preview line is not a zone edit request: %s", err)
+		}
+		zones[req.ZoneName] = true
+	}
+
+	if len(zones) != 2 || !zones["a.example"] || !zones["b.example"] {
+		t.Errorf("Expected one preview line per zone, got %v", zones)
+	}
+}
+
+func TestClient_StatusPollsAreCapped(t *testing.T) {
+	var zones []*cscdm.Zone
+	for i := 0; i < 6; i++ {
+		zones = append(zones, &cscdm.Zone{ZoneName: fmt.Sprintf("zone%d.example", i)})
+	}
+	fake := newFakeCsc(t, zones...)
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	fake.editStatus = func(editId string) string {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return "COMPLETED"
+	}
+
+	client := &cscdm.Client{
+		BaseUrl:            fake.URL + "/",
+		PollInterval:       10 * time.Millisecond,
+		FlushIdleDuration:  50 * time.Millisecond,
+		MaxConcurrentPolls: 2,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	var wg sync.WaitGroup
+	for _, zone := range zones {
+		wg.Add(1)
+		go func(zoneName string) {
+			defer wg.Done()
+
+			_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+				ZoneName: zoneName,
+				ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+			})
+			if err != nil {
+				t.Errorf("Add to %s failed: %s", zoneName, err)
+			}
+		}(zone.ZoneName)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent status polls, saw %d", peak)
+	}
+	if peak == 0 {
+		t.Errorf("Expected status polls to be made")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestClient_ReportsOrphanedChannelsAfterFlush(t *testing.T) {
+	var warnings syncBuffer
+	t.Cleanup(cscdm.SetWarnOutput(&warnings))
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		// Store the record under a different value, so the client cannot
+		// match it back to the waiting caller.
+		for i := range req.Edits {
+			req.Edits[i].NewValue += "0"
+		}
+		fake.mu.Lock()
+		for _, edit := range req.Edits {
+			fake.apply(fake.zones[req.ZoneName], edit)
+		}
+		fake.mu.Unlock()
+
+		writeJson(w, http.StatusCreated, map[string]any{
+			"links": map[string]string{"status": fake.URL + "/zones/edits/status/1"},
+		})
+		return true
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "AAAA", NewKey: "www", NewValue: "2001:db8::a"},
+	})
+	if err == nil {
+		t.Fatal("Expected the unmatched record to fail")
+	}
+
+	if got := warnings.String(); !strings.Contains(got, "orphaned return channel(s) after flush: example.com:AAAA:www:2001:db8::a") {
+		t.Errorf("Expected the orphaned channel id to be reported, got %q", got)
+	}
+}
+
+func TestClient_MatchesAaaaStoredInAnotherSpellingAfterFlush(t *testing.T) {
+	var warnings syncBuffer
+	t.Cleanup(cscdm.SetWarnOutput(&warnings))
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		// Store the address upper-cased, an equivalent spelling of it.
+		for i := range req.Edits {
+			req.Edits[i].NewValue = strings.ToUpper(req.Edits[i].NewValue)
+		}
+		fake.mu.Lock()
+		for _, edit := range req.Edits {
+			fake.apply(fake.zones[req.ZoneName], edit)
+		}
+		fake.mu.Unlock()
+
+		writeJson(w, http.StatusCreated, map[string]any{
+			"links": map[string]string{"status": fake.URL + "/zones/edits/status/1"},
+		})
+		return true
+	}
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "AAAA", NewKey: "www", NewValue: "2001:db8::a"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the upper-cased address to be matched, got: %s", err)
+	}
+	if record.Value != "2001:DB8::A" {
+		t.Errorf("Expected the record as CSC stored it, got %+v", record)
+	}
+
+	if got := warnings.String(); strings.Contains(got, "orphaned") {
+		t.Errorf("Expected no orphaned channels, got %q", got)
+	}
+}
+
+func TestClient_FlushSubmitsWithoutWaitingForIdleTimer(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: time.Hour,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+		})
+		done <- err
+	}()
+
+	// Give the action time to be queued.
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %s", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Add failed: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the queued action to be answered when Flush returned")
+	}
+
+	if n := len(fake.submittedEdits()); n != 1 {
+		t.Errorf("Expected 1 zone edit request, got %d", n)
+	}
+}
+
+func TestClient_CancelledEditIsTerminal(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	var mu sync.Mutex
+	statuses := []string{"PENDING", "IN_PROGRESS", "PARTIAL", "CANCELLED"}
+	polls := 0
+	fake.editStatus = func(editId string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		return status
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "CANCELLED") {
+		t.Fatalf("Expected a CANCELLED edit to fail, got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if polls != len(statuses) {
+		t.Errorf("Expected polling to continue through transient statuses and stop at CANCELLED, got %d polls", polls)
+	}
+}
+
+// lockedZoneFake returns a fake that rejects the first locked edit
+// submissions with OPEN_ZONE_EDITS, and a client that gives up on the lock
+// straight away so only re-queueing can get the edit through.
+func lockedZoneFake(t *testing.T, locked int, requeues int) (*fakeCsc, *cscdm.Client) {
+	t.Helper()
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	var mu sync.Mutex
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if locked == 0 {
+			return false
+		}
+		locked--
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "OPEN_ZONE_EDITS", Description: "zone has open edits"})
+		return true
+	}
+
+	client := fake.newClient(t)
+	client.RetryPolicy = func(int, int, error) (bool, time.Duration) { return false, 0 }
+	client.ZoneLockRequeues = requeues
+
+	return fake, client
+}
+
+func TestClient_RequeuesBatchUntilZoneLockClears(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+	fake, client := lockedZoneFake(t, 2, 3)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the edit to succeed once the lock cleared, got: %s", err)
+	}
+	if record.Key != "www" {
+		t.Errorf("Expected record for 'www', got %+v", record)
+	}
+
+	if submitted := fake.submittedEdits(); len(submitted) != 3 {
+		t.Errorf("Expected 2 rejected submissions and 1 accepted, got %d", len(submitted))
+	}
+}
+
+func TestClient_RequeuesAreBounded(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+	fake, client := lockedZoneFake(t, 10, 2)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "OPEN_ZONE_EDITS") {
+		t.Fatalf("Expected the lock conflict to be reported after the re-queues ran out, got: %v", err)
+	}
+
+	if submitted := fake.submittedEdits(); len(submitted) != 3 {
+		t.Errorf("Expected the first submission and 2 re-queued ones, got %d", len(submitted))
+	}
+}
+
+// blockingLocker holds the lock on one zone until the caller gives up and
+// grants every other zone at once.
+type blockingLocker struct {
+	zoneName string
+}
+
+func (l blockingLocker) Lock(ctx context.Context, zoneName string) (func(), error) {
+	if zoneName == l.zoneName {
+		<-ctx.Done()
+		return nil, context.Cause(ctx)
+	}
+	return func() {}, nil
+}
+
+func TestClient_FailFastSkipsOnlyUnsubmittedZones(t *testing.T) {
+	fake := newFakeCsc(t,
+		&cscdm.Zone{ZoneName: "broken.example"},
+		&cscdm.Zone{ZoneName: "slow.example"},
+		&cscdm.Zone{ZoneName: "waiting.example"},
+	)
+	slowSubmitted := make(chan struct{})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		switch req.ZoneName {
+		case "slow.example":
+			close(slowSubmitted)
+			return false
+		case "broken.example":
+			// Fail only once the slow zone's edit has been accepted.
+			<-slowSubmitted
+			writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "INVALID_RECORD", Description: "record value is invalid"})
+			return true
+		}
+		return false
+	}
+	// The slow zone's edit completes only after the broken zone has failed.
+	var brokenFailed atomic.Bool
+	fake.editStatus = func(editId string) string {
+		if brokenFailed.Load() {
+			return "COMPLETED"
+		}
+		return "PENDING"
+	}
+
+	client := fake.newClient(t)
+	client.FailFast = true
+	// The waiting zone cannot be submitted until the batch gives up on it.
+	client.ZoneLocker = blockingLocker{zoneName: "waiting.example"}
+
+	errs := make(map[string]chan error)
+	for _, zoneName := range []string{"broken.example", "slow.example", "waiting.example"} {
+		errs[zoneName] = make(chan error, 1)
+		go func(zoneName string) {
+			_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+				ZoneName: zoneName,
+				ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+			})
+			errs[zoneName] <- err
+		}(zoneName)
+	}
+
+	await := func(zoneName string) error {
+		select {
+		case err := <-errs[zoneName]:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", zoneName)
+			return nil
+		}
+	}
+
+	if err := await("broken.example"); err == nil || !strings.Contains(err.Error(), "INVALID_RECORD") {
+		t.Errorf("Expected the broken zone to fail, got: %v", err)
+	}
+	brokenFailed.Store(true)
+
+	if err := await("waiting.example"); err == nil || !strings.Contains(err.Error(), "INVALID_RECORD") {
+		t.Errorf("Expected the unsubmitted zone to be skipped with the first zone's error, got: %v", err)
+	}
+	if err := await("slow.example"); err != nil {
+		t.Errorf("Expected the submitted zone to be waited on to completion, got: %s", err)
+	}
+	for _, req := range fake.submittedEdits() {
+		if req.ZoneName == "waiting.example" {
+			t.Errorf("Expected the waiting zone never to be submitted")
+		}
+	}
+}
+
+func TestClient_SharedLockerSerializesZoneEdits(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	// Hold the first edit open until released, so the second client can
+	// only get its edit in by ignoring the lock.
+	var released atomic.Bool
+	fake.editStatus = func(editId string) string {
+		if released.Load() {
+			return "COMPLETED"
+		}
+		return "PENDING"
+	}
+
+	locker := &cscdm.FileLocker{Dir: t.TempDir()}
+	first, second := fake.newClient(t), fake.newClient(t)
+	first.ZoneLocker, second.ZoneLocker = locker, locker
+
+	errs := make(chan error, 2)
+	add := func(client *cscdm.Client, key string) {
+		_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: key, NewValue: "10.0.0.1"},
+		})
+		errs <- err
+	}
+
+	go add(first, "www")
+	time.Sleep(150 * time.Millisecond)
+	go add(second, "api")
+	time.Sleep(300 * time.Millisecond)
+
+	if edits := fake.submittedEdits(); len(edits) != 1 {
+		t.Errorf("Expected the second client to wait for the zone lock, got %d edits submitted", len(edits))
+	}
+
+	released.Store(true)
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Errorf("Expected both adds to succeed once the lock was released, got: %s", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the locked edits")
+		}
+	}
+
+	if edits := fake.submittedEdits(); len(edits) != 2 {
+		t.Errorf("Expected both edits to be submitted in turn, got %d", len(edits))
+	}
+}
+
+func TestClient_AsyncWaitSubmitsNextBatchWhileFirstApplies(t *testing.T) {
+	// slowPollsWithoutAsyncWait is how many times the first edit is polled
+	// after the second batch is queued before the first is released, when
+	// the second batch is expected to stay queued.
+	const slowPollsWithoutAsyncWait = 5
+
+	for _, asyncWait := range []bool{false, true} {
+		t.Run(fmt.Sprintf("AsyncWait=%t", asyncWait), func(t *testing.T) {
+			fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "slow.example"}, &cscdm.Zone{ZoneName: "fast.example"})
+
+			// The first edit polled stays pending until released, reporting
+			// each poll on slowPolled.
+			var released atomic.Bool
+			var firstEdit sync.Once
+			var slowEditId string
+			slowPolled := make(chan struct{}, 100)
+			fake.editStatus = func(editId string) string {
+				firstEdit.Do(func() { slowEditId = editId })
+				if editId == slowEditId && !released.Load() {
+					select {
+					case slowPolled <- struct{}{}:
+					default:
+					}
+					return "PENDING"
+				}
+				return "COMPLETED"
+			}
+			var fastSubmittedEarly atomic.Bool
+			fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+				if req.ZoneName == "fast.example" && !released.Load() {
+					fastSubmittedEarly.Store(true)
+				}
+				return false
+			}
+
+			client := fake.newClient(t)
+			client.AsyncWait = asyncWait
+
+			add := func(zoneName string, done chan<- error) {
+				_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+					ZoneName: zoneName,
+					ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+				})
+				done <- err
+			}
+			awaitSlowPoll := func() {
+				select {
+				case <-slowPolled:
+				case <-time.After(5 * time.Second):
+					t.Fatal("Timed out waiting for the first edit to be polled")
+				}
+			}
+
+			slow, fast := make(chan error, 1), make(chan error, 1)
+			go add("slow.example", slow)
+			awaitSlowPoll()
+
+			start := time.Now()
+			go add("fast.example", fast)
+
+			if asyncWait {
+				select {
+				case err := <-fast:
+					// Hand the result back for the final check.
+					fast <- err
+					t.Logf("second batch completed after %s, %d poll(s) of the first", time.Since(start), len(slowPolled))
+				case <-time.After(5 * time.Second):
+					t.Fatal("Expected the second batch to complete while the first was still applying")
+				}
+			} else {
+				for i := 0; i < slowPollsWithoutAsyncWait; i++ {
+					awaitSlowPoll()
+				}
+				select {
+				case <-fast:
+					t.Fatal("Expected the second batch to wait behind the first without AsyncWait")
+				default:
+				}
+			}
+
+			released.Store(true)
+			for _, done := range []chan error{slow, fast} {
+				select {
+				case err := <-done:
+					if err != nil {
+						t.Errorf("Expected both batches to succeed, got: %s", err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("Timed out waiting for the batches to complete")
+				}
+			}
+			if fastSubmittedEarly.Load() != asyncWait {
+				t.Errorf("Expected the second batch to be submitted before the first completed only with AsyncWait, got %t", fastSubmittedEarly.Load())
+			}
+		})
+	}
+}
+
+func TestClient_FlushGracePeriodFlushesAfterLastEnqueue(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: time.Hour,
+		FlushGracePeriod:  50 * time.Millisecond,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	start := time.Now()
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("Expected the grace period rather than the idle interval to drive the flush, took %s", elapsed)
+	}
+}
+
+func TestClient_FlushIdleDurationPicksUpRequeuedBatches(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	var mu sync.Mutex
+	locked := true
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !locked {
+			return false
+		}
+		locked = false
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "OPEN_ZONE_EDITS", Description: "zone has open edits"})
+		return true
+	}
+
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: 300 * time.Millisecond,
+		FlushGracePeriod:  10 * time.Millisecond,
+		ZoneLockRequeues:  1,
+		RetryPolicy:       func(int, int, error) (bool, time.Duration) { return false, 0 },
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	start := time.Now()
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the re-queued edit to succeed, got: %s", err)
+	}
+
+	// The first submission follows the grace period; the re-queued batch
+	// waits for the idle interval since nothing new was enqueued.
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected the re-queued batch to wait for the idle interval, took %s", elapsed)
+	}
+	if submitted := fake.submittedEdits(); len(submitted) != 2 {
+		t.Errorf("Expected a rejected and an accepted submission, got %d", len(submitted))
+	}
+}
+
+func TestClient_StatusCallbackCalledPerPoll(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	var polls atomic.Int32
+	fake.editStatus = func(editId string) string {
+		if polls.Add(1) < 3 {
+			return "IN_PROGRESS"
+		}
+		return "COMPLETED"
+	}
+
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: 50 * time.Millisecond,
+	}
+
+	var mu sync.Mutex
+	var statuses []string
+	client.StatusCallback = func(editId string, status string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if editId == "" {
+			t.Errorf("Expected the callback to receive the edit id")
+		}
+		statuses = append(statuses, status)
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := []string{"IN_PROGRESS", "IN_PROGRESS", "COMPLETED"}
+	if !slices.Equal(statuses, expected) {
+		t.Errorf("Expected the callback to see %v, got %v", expected, statuses)
+	}
+}
+
+func TestClient_AsyncWaitZoneFailureLeavesNextBatchForZone(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	// The first edit polled stays pending until failed, the other until
+	// released, each reporting its polls.
+	var failFirst, releaseSecond atomic.Bool
+	var firstEdit sync.Once
+	var firstEditId string
+	firstPolled, secondPolled := make(chan struct{}, 100), make(chan struct{}, 100)
+	fake.editStatus = func(editId string) string {
+		firstEdit.Do(func() { firstEditId = editId })
+		if editId == firstEditId {
+			if failFirst.Load() {
+				return "FAILED"
+			}
+			select {
+			case firstPolled <- struct{}{}:
+			default:
+			}
+			return "PENDING"
+		}
+		if releaseSecond.Load() {
+			return "COMPLETED"
+		}
+		select {
+		case secondPolled <- struct{}{}:
+		default:
+		}
+		return "PENDING"
+	}
+
+	client := fake.newClient(t)
+	client.AsyncWait = true
+	// Let the client stop if the test gives up early.
+	t.Cleanup(func() {
+		failFirst.Store(true)
+		releaseSecond.Store(true)
+	})
+
+	add := func(key string, done chan<- error) {
+		_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: key, NewValue: "10.0.0.1"},
+		})
+		done <- err
+	}
+	await := func(polled <-chan struct{}, what string) {
+		select {
+		case <-polled:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for the %s edit to be polled", what)
+		}
+	}
+
+	first, second := make(chan error, 1), make(chan error, 1)
+	go add("www", first)
+	await(firstPolled, "first")
+	go add("api", second)
+	await(secondPolled, "second")
+
+	failFirst.Store(true)
+	select {
+	case err := <-first:
+		if err == nil || !strings.Contains(err.Error(), "FAILED") {
+			t.Errorf("Expected the first batch to fail, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the first batch to fail")
+	}
+
+	select {
+	case err := <-second:
+		t.Fatalf("Expected the second batch to wait for its own edit, got answered with: %v", err)
+	default:
+	}
+
+	releaseSecond.Store(true)
+	select {
+	case err := <-second:
+		if err != nil {
+			t.Errorf("Expected the second batch to succeed, got: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the second batch")
+	}
+}
@@ -0,0 +1,67 @@
+package cscdm_test
+
+import (
+	"context"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestParseCaaValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`issue "letsencrypt.org"`, `0 issue "letsencrypt.org"`},
+		{`128 ISSUEWILD ";"`, `128 issuewild ";"`},
+		{`0 iodef "mailto:security@example.com"`, `0 iodef "mailto:security@example.com"`},
+		{`0 issue "say \"hi\""`, `0 issue "say \"hi\""`},
+		{`256 issue "letsencrypt.org"`, ""},
+		{`0 contactemail "a@example.com"`, ""},
+		{`x issue "letsencrypt.org"`, ""},
+		{`issue`, ""},
+		{`0 issue "unterminated`, ""},
+	}
+
+	for _, test := range tests {
+		caa, err := cscdm.ParseCaaValue(test.value)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("Expected %q to be rejected", test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected %q to parse, got error: %s", test.value, err)
+			continue
+		}
+		if caa.String() != test.want {
+			t.Errorf("Expected %q to format as %q, got %q", test.value, test.want, caa.String())
+		}
+	}
+}
+
+func TestClient_AddCaaRecord(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{
+			Action:     "ADD",
+			RecordType: "CAA",
+			NewKey:     "@",
+			NewValue:   `issue "letsencrypt.org"`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	if record.Value != `0 issue "letsencrypt.org"` {
+		t.Errorf("Expected the record to be found in its encoded form, got %+v", record)
+	}
+
+	if sent := fake.submittedEdits()[0].Edits[0].NewValue; sent != `0 issue "letsencrypt.org"` {
+		t.Errorf("Expected flags to be filled in, sent %q", sent)
+	}
+}
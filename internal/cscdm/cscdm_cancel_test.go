@@ -0,0 +1,63 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+func TestClient_CancelStopsPolling(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	var polls atomic.Int32
+	fake.editStatus = func(editId string) string {
+		polls.Add(1)
+		return "IN_PROGRESS"
+	}
+	client := fake.newClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := client.PerformRecordAction(ctx, &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to end the call, got %v", err)
+	}
+
+	// The batch notices its only caller gave up within a poll interval.
+	time.Sleep(100 * time.Millisecond)
+	before := polls.Load()
+	time.Sleep(200 * time.Millisecond)
+	if after := polls.Load(); after != before {
+		t.Errorf("Expected polling to stop once the caller gave up, got %d more polls", after-before)
+	}
+}
+
+func TestClient_CancelDropsQueuedAction(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.PerformRecordAction(ctx, &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancelled context to end the call, got %v", err)
+	}
+
+	if err := client.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %s", err)
+	}
+	if n := len(fake.submittedEdits()); n != 0 {
+		t.Errorf("Expected the abandoned action not to be submitted, got %d zone edits", n)
+	}
+}
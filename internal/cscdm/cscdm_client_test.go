@@ -0,0 +1,367 @@
+package cscdm_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/util"
+	"testing"
+	"time"
+)
+
+func TestClient_DoubleConfigureStartsOneFlushLoop(t *testing.T) {
+	client := &cscdm.Client{}
+	client.Configure("test-key", "test-token")
+	firstLoopDone := cscdm.FlushLoopDone(client)
+
+	client.Configure("test-key", "test-token")
+
+	stopped := make(chan struct{})
+	go func() {
+		client.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop hung")
+	}
+
+	// Stop waits for the loop it knows about, so the loop started by the
+	// first Configure must be that same loop.
+	select {
+	case <-firstLoopDone:
+	default:
+		t.Error("Expected Stop to end the flush loop started by the first Configure")
+	}
+}
+
+func TestClient_WithCredentialsCachesPerPair(t *testing.T) {
+	client := &cscdm.Client{}
+	client.Configure("key-a", "token-a")
+	defer client.Stop()
+
+	if scoped := client.WithCredentials("key-a", "token-a"); scoped != client {
+		t.Error("Expected the provider's own credentials to return the same client")
+	}
+
+	scopedB := client.WithCredentials("key-b", "token-b")
+	if scopedB == client {
+		t.Fatal("Expected different credentials to return a scoped client")
+	}
+	if again := client.WithCredentials("key-b", "token-b"); again != scopedB {
+		t.Error("Expected the scoped client to be reused for the same credential pair")
+	}
+	if other := client.WithCredentials("key-b", "token-c"); other == scopedB {
+		t.Error("Expected a different credential pair to get its own client")
+	}
+}
+
+func TestClient_SynchronousSubmitsImmediately(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+
+	client := &cscdm.Client{
+		BaseUrl:           fake.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: time.Hour,
+		Synchronous:       true,
+	}
+	client.Configure("test-key", "test-token")
+
+	select {
+	case <-cscdm.FlushLoopDone(client):
+	default:
+		t.Error("Expected no flush loop to be running")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+		})
+		if err != nil {
+			t.Errorf("Add failed: %s", err)
+			return
+		}
+		if record.Key != "www" {
+			t.Errorf("Expected record 'www', got %+v", record)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Synchronous add waited on the flush interval")
+	}
+
+	client.Stop()
+	client.Stop()
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		takesId  bool
+		valid    bool
+	}{
+		{cscdm.EDIT_PATH, false, true},
+		{cscdm.EDIT_STATUS_PATH, true, true},
+		{cscdm.EDIT_CANCEL_PATH, true, true},
+		{"v3/zones/edits", false, true},
+		{"zones/edits/%s", false, false},
+		{"zones/edits/status", true, false},
+		{"zones/%s/edits/%s", true, false},
+		{"zones/edits/%d", true, false},
+		{"", false, false},
+	}
+
+	for _, test := range tests {
+		err := cscdm.ValidatePathTemplate(test.template, test.takesId)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidatePathTemplate(%q, %t) returned %v, expected valid = %t", test.template, test.takesId, err, test.valid)
+		}
+	}
+}
+
+func TestNormalizeApiUrl(t *testing.T) {
+	tests := []struct {
+		apiUrl   string
+		expected string
+		valid    bool
+	}{
+		{cscdm.CSC_DOMAIN_MANAGER_API_URL, cscdm.CSC_DOMAIN_MANAGER_API_URL, true},
+		{"https://sandbox.cscglobal.com/dbs/api/v2", "https://sandbox.cscglobal.com/dbs/api/v2/", true},
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080/", true},
+		{"/dbs/api/v2/", "", false},
+		{"apis.cscglobal.com/dbs/api/v2/", "", false},
+		{"ftp://apis.cscglobal.com/", "", false},
+		{"https://apis.cscglobal.com/dbs/api/v2/?debug=1", "", false},
+		{"https://%zz/", "", false},
+	}
+
+	for _, test := range tests {
+		normalized, err := cscdm.NormalizeApiUrl(test.apiUrl)
+		if (err == nil) != test.valid {
+			t.Errorf("NormalizeApiUrl(%q) returned %v, expected valid = %t", test.apiUrl, err, test.valid)
+			continue
+		}
+		if normalized != test.expected {
+			t.Errorf("NormalizeApiUrl(%q) = %q, expected %q", test.apiUrl, normalized, test.expected)
+		}
+	}
+}
+
+func TestCheckApiVersion(t *testing.T) {
+	tests := []struct {
+		apiUrl  string
+		version string
+		valid   bool
+	}{
+		{cscdm.CSC_DOMAIN_MANAGER_API_URL, cscdm.CSC_API_VERSION, true},
+		{"https://apis.cscglobal.com/dbs/api/v3/", "v3", false},
+		{"https://proxy.internal/cscdm/", "", true},
+		{"http://127.0.0.1:8080/", "", true},
+		{"https://apis.cscglobal.com/dbs/api/v2", "v2", true},
+	}
+
+	for _, test := range tests {
+		if version := cscdm.ApiVersion(test.apiUrl); version != test.version {
+			t.Errorf("ApiVersion(%q) = %q, expected %q", test.apiUrl, version, test.version)
+		}
+		if err := cscdm.CheckApiVersion(test.apiUrl); (err == nil) != test.valid {
+			t.Errorf("CheckApiVersion(%q) returned %v, expected valid = %t", test.apiUrl, err, test.valid)
+		}
+	}
+}
+
+func TestClient_PingClassifiesFailures(t *testing.T) {
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Expected ping to succeed, got: %s", err)
+	}
+
+	statusCode = http.StatusUnauthorized
+	if err := client.Ping(context.Background()); !errors.Is(err, cscdm.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for a 401, got: %v", err)
+	}
+
+	statusCode = http.StatusInternalServerError
+	if err := client.Ping(context.Background()); err == nil || errors.Is(err, cscdm.ErrUnauthorized) || errors.Is(err, cscdm.ErrUnreachable) {
+		t.Errorf("Expected an unexpected status error for a 500, got: %v", err)
+	}
+
+	server.Close()
+	if err := client.Ping(context.Background()); !errors.Is(err, cscdm.ErrUnreachable) {
+		t.Errorf("Expected ErrUnreachable once the server is gone, got: %v", err)
+	}
+}
+
+func TestZoneEditErr_ExplainsKnownCodes(t *testing.T) {
+	known := &cscdm.ZoneEditErr{Code: "OPEN_ZONE_EDITS", Description: "zone has open edits", Value: "example.com"}
+	if explanation := cscdm.ExplainErrorCode(known.Code); explanation == "" || !strings.Contains(known.Error(), explanation) {
+		t.Errorf("Expected %q to carry an explanation, got: %s", known.Code, known.Error())
+	}
+	if !strings.HasPrefix(known.Error(), `OPEN_ZONE_EDITS: zone has open edits: "example.com"`) {
+		t.Errorf("Expected the raw code, description and value to be kept, got: %s", known.Error())
+	}
+
+	unknown := &cscdm.ZoneEditErr{Code: "SOMETHING_NEW", Description: "new failure", Value: "x"}
+	if cscdm.ExplainErrorCode(unknown.Code) != "" {
+		t.Errorf("Expected no explanation for an unknown code")
+	}
+	if got, want := unknown.Error(), `SOMETHING_NEW: new failure: "x"`; got != want {
+		t.Errorf("Expected unknown codes to fall back to %q, got %q", want, got)
+	}
+}
+
+func TestClient_ChangeIdSentOnWritesOnly(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com", A: []cscdm.ZoneRecord{}})
+
+	var mu sync.Mutex
+	changeIds := make(map[string][]string)
+	handler := fake.Config.Handler
+	fake.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		changeIds[r.Method] = append(changeIds[r.Method], r.Header.Get(cscdm.CHANGE_ID_HEADER))
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	})
+
+	client := fake.newClient(t)
+	client.ChangeId = "CHG0012345"
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if ids := changeIds[http.MethodPost]; len(ids) != 1 || ids[0] != "CHG0012345" {
+		t.Errorf("Expected the edit to carry the change ID, got %q", ids)
+	}
+	for _, id := range changeIds[http.MethodGet] {
+		if id != "" {
+			t.Errorf("Expected reads to carry no change ID, got %q", id)
+		}
+	}
+}
+
+func TestClient_RequestTimeoutBoundsHungRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	// The timeout bounds each attempt, so transport retries are disabled to
+	// time a single one.
+	client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true, RequestTimeout: 50 * time.Millisecond, IdempotentRetries: -1}
+	client.Configure("test-key", "test-token")
+
+	start := time.Now()
+	if _, err := client.RefreshZone(context.Background(), "example.com"); err == nil {
+		t.Errorf("Expected a request CSC never answers to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the request to time out after 50ms, took %s", elapsed)
+	}
+}
+
+func TestClient_SendsUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true, UserAgent: cscdm.UserAgent("1.2.0")}
+	client.Configure("test-key", "test-token")
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %s", err)
+	}
+	if userAgent != "terraform-provider-cscdm/1.2.0 (+terraform)" {
+		t.Errorf("Unexpected User-Agent %q", userAgent)
+	}
+}
+
+func TestClient_RoutesThroughProxy(t *testing.T) {
+	var host, proxyAuthorization string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Host
+		proxyAuthorization = r.Header.Get("Proxy-Authorization")
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyUrl, err := util.ParseProxyUrl("http://user:secret@" + proxy.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("ParseProxyUrl failed: %s", err)
+	}
+
+	client := &cscdm.Client{BaseUrl: "http://cscdm.invalid/", Synchronous: true, ProxyUrl: proxyUrl}
+	client.Configure("test-key", "test-token")
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %s", err)
+	}
+	if host != "cscdm.invalid" {
+		t.Errorf("Expected the proxy to be asked for cscdm.invalid, got %q", host)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret")); proxyAuthorization != want {
+		t.Errorf("Expected Proxy-Authorization %q, got %q", want, proxyAuthorization)
+	}
+}
+
+func TestClient_TlsVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	tests := []struct {
+		name   string
+		client *cscdm.Client
+		valid  bool
+	}{
+		{"system pool", &cscdm.Client{}, false},
+		{"custom CA", &cscdm.Client{RootCAs: pool}, true},
+		{"insecure", &cscdm.Client{InsecureSkipVerify: true}, true},
+	}
+
+	for _, test := range tests {
+		test.client.BaseUrl = server.URL + "/"
+		test.client.Synchronous = true
+		test.client.IdempotentRetries = -1
+		test.client.Configure("test-key", "test-token")
+
+		if err := test.client.Ping(context.Background()); (err == nil) != test.valid {
+			t.Errorf("%s: Ping returned %v, expected valid = %t", test.name, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cscdm_test
+
+import (
+	"encoding/json"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestZoneRecord_UnmarshalTolerantNumbers(t *testing.T) {
+	tests := []struct {
+		body     string
+		ttl      int64
+		priority int64
+	}{
+		{`{"id":"1","ttl":300,"priority":10}`, 300, 10},
+		{`{"id":"1","ttl":"300","priority":"10"}`, 300, 10},
+		{`{"id":"1","ttl":300.0,"priority":" 10 "}`, 300, 10},
+		{`{"id":"1","ttl":"3e2"}`, 300, 0},
+		{`{"id":"1","ttl":null,"priority":""}`, 0, 0},
+		{`{"id":"1"}`, 0, 0},
+	}
+
+	for _, test := range tests {
+		var record cscdm.ZoneRecord
+		if err := json.Unmarshal([]byte(test.body), &record); err != nil {
+			t.Errorf("Unmarshal of %s failed: %s", test.body, err)
+			continue
+		}
+		if record.Id != "1" || record.Ttl != test.ttl || record.Priority != test.priority {
+			t.Errorf("Unmarshal of %s gave %+v", test.body, record)
+		}
+	}
+
+	for _, body := range []string{`{"ttl":"abc"}`, `{"ttl":300.5}`, `{"priority":true}`, `{"ttl":"1e30"}`} {
+		var record cscdm.ZoneRecord
+		if err := json.Unmarshal([]byte(body), &record); err == nil {
+			t.Errorf("Expected unmarshal of %s to fail", body)
+		}
+	}
+}
+
+func TestZone_UnmarshalStringSrvFields(t *testing.T) {
+	body := `{"zoneName":"example.com","srv":[{"id":"1","key":"_sip._tcp","value":"sip.example.com","ttl":"600","priority":"10","weight":"5","port":"5060"}]}`
+
+	var zone cscdm.Zone
+	if err := json.Unmarshal([]byte(body), &zone); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+
+	srv := zone.SRV[0]
+	if srv.Key != "_sip._tcp" || srv.Value != "sip.example.com" || srv.Ttl != 600 || srv.Priority != 10 || srv.Weight != 5 || srv.Port != 5060 {
+		t.Errorf("Unexpected SRV record %+v", srv)
+	}
+
+	if err := json.Unmarshal([]byte(`{"srv":[{"port":70000}]}`), &zone); err == nil {
+		t.Errorf("Expected an out of range port to fail")
+	}
+}
@@ -0,0 +1,127 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+func dependencyZone() *cscdm.Zone {
+	return &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+		CNAME:    []cscdm.ZoneRecord{{Id: "2", Key: "blog", Value: "www.example.com"}},
+		MX:       []cscdm.ZoneRecord{{Id: "3", Key: "@", Value: "www", Priority: 10}},
+	}
+}
+
+func purgeAction(recordType string, key string, value string) *cscdm.RecordAction {
+	return &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "PURGE", RecordType: recordType, CurrentKey: key, CurrentValue: value},
+	}
+}
+
+func TestClient_PurgeReportsOrphanedRecords(t *testing.T) {
+	fake := newFakeCsc(t, dependencyZone())
+	client := fake.newClient(t)
+	client.DependencyChecks = true
+
+	orphans, err := client.PerformRecordPurge(context.Background(), purgeAction("A", "www", "10.0.0.1"))
+	if err != nil {
+		t.Fatalf("Purge failed: %s", err)
+	}
+
+	if len(orphans) != 2 {
+		t.Errorf("Expected the CNAME and MX records to be reported, got %v", orphans)
+	}
+}
+
+func TestClient_PurgeIgnoresDependentsPurgedInSameBatch(t *testing.T) {
+	fake := newFakeCsc(t, dependencyZone())
+	client := fake.newClient(t)
+	client.DependencyChecks = true
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		if _, err := client.PerformRecordPurge(context.Background(), purgeAction("CNAME", "blog", "www.example.com")); err != nil {
+			t.Errorf("CNAME purge failed: %s", err)
+		}
+	}()
+
+	orphans, err := client.PerformRecordPurge(context.Background(), purgeAction("A", "www", "10.0.0.1"))
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("Purge failed: %s", err)
+	}
+
+	if len(orphans) != 1 || orphans[0] != "MX @ -> www" {
+		t.Errorf("Expected only the MX record to be reported, got %v", orphans)
+	}
+}
+
+func TestClient_PurgeSkipsChecksByDefault(t *testing.T) {
+	fake := newFakeCsc(t, dependencyZone())
+	client := fake.newClient(t)
+
+	orphans, err := client.PerformRecordPurge(context.Background(), purgeAction("A", "www", "10.0.0.1"))
+	if err != nil {
+		t.Fatalf("Purge failed: %s", err)
+	}
+
+	if len(orphans) != 0 {
+		t.Errorf("Expected no dependency checks, got %v", orphans)
+	}
+}
+
+func TestClient_DependencyChecksReadZoneWithoutHoldingQueue(t *testing.T) {
+	fake := newFakeCsc(t, dependencyZone())
+	reading := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	fake.onZoneRead = func(zoneName string) {
+		once.Do(func() {
+			close(reading)
+			<-release
+		})
+	}
+	client := fake.newClient(t)
+	client.DependencyChecks = true
+
+	purged := make(chan error, 1)
+	go func() {
+		_, err := client.PerformRecordPurge(context.Background(), purgeAction("A", "www", "10.0.0.1"))
+		purged <- err
+	}()
+	<-reading
+
+	// While the zone is being read, another caller can still enqueue and
+	// give up, rather than wait for the read behind batchMutex.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	queued := make(chan error, 1)
+	go func() {
+		_, err := client.PerformRecordAction(ctx, purgeAction("CNAME", "blog", "www.example.com"))
+		queued <- err
+	}()
+
+	select {
+	case err := <-queued:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the caller to give up on its own deadline, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out enqueueing while the zone was being read")
+	}
+
+	close(release)
+	if err := <-purged; err != nil {
+		t.Errorf("Purge failed: %s", err)
+	}
+}
@@ -0,0 +1,293 @@
+package cscdm_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+// fakeCsc is an in-memory stand-in for the CSC Domain Manager zone API. It
+// applies submitted edits to its zones immediately and reports every edit as
+// COMPLETED unless a hook says otherwise.
+type fakeCsc struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	zones    map[string]*cscdm.Zone
+	edits    []cscdm.ZoneEditReq
+	nextId   int
+	requests int
+
+	// onEdit, when set, may take over the response to a zone edit
+	// submission by returning handled = true.
+	onEdit func(w http.ResponseWriter, req cscdm.ZoneEditReq) (handled bool)
+	// editStatus, when set, returns the status reported for an edit.
+	editStatus func(editId string) string
+	// onZoneRead, when set, is called before a zone is read, and may block
+	// to hold the response.
+	onZoneRead func(zoneName string)
+}
+
+func newFakeCsc(t *testing.T, zones ...*cscdm.Zone) *fakeCsc {
+	t.Helper()
+
+	f := &fakeCsc{zones: make(map[string]*cscdm.Zone)}
+	for _, zone := range zones {
+		f.zones[zone.ZoneName] = zone
+	}
+
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// newClient returns a client pointed at the fake with short timings.
+func (f *fakeCsc) newClient(t *testing.T) *cscdm.Client {
+	t.Helper()
+
+	client := &cscdm.Client{
+		BaseUrl:           f.URL + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: 50 * time.Millisecond,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	return client
+}
+
+// submittedEdits returns a copy of every zone edit request received so far.
+func (f *fakeCsc) submittedEdits() []cscdm.ZoneEditReq {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]cscdm.ZoneEditReq(nil), f.edits...)
+}
+
+// requestCount returns the number of requests received so far.
+func (f *fakeCsc) requestCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests
+}
+
+func (f *fakeCsc) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case r.Method == http.MethodPost && path == "zones/edits":
+		f.handleEdit(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "zones/edits/status/"):
+		editId := strings.TrimPrefix(path, "zones/edits/status/")
+		status := "COMPLETED"
+		if f.editStatus != nil {
+			status = f.editStatus(editId)
+		}
+		writeJson(w, http.StatusOK, map[string]any{"content": map[string]string{"status": status}})
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "zones/edits/"):
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && path == "zones":
+		f.mu.Lock()
+		var zones []cscdm.Zone
+		for _, zone := range f.zones {
+			zones = append(zones, *zone)
+		}
+		f.mu.Unlock()
+		writeJson(w, http.StatusOK, map[string]any{"zones": zones})
+	case r.Method == http.MethodPost && path == "zones":
+		var req cscdm.ZoneCreateReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "BAD_REQUEST", Description: err.Error()})
+			return
+		}
+		f.mu.Lock()
+		_, exists := f.zones[req.ZoneName]
+		if !exists {
+			f.zones[req.ZoneName] = &cscdm.Zone{
+				ZoneName:    req.ZoneName,
+				HostingType: req.HostingType,
+				Status:      "ACTIVE",
+				SOA:         cscdm.ZoneSoaRecord{Serial: 1, Refresh: 3600, Retry: 600, Expire: 1209600, TtlMin: 300},
+			}
+		}
+		f.mu.Unlock()
+		if exists {
+			writeJson(w, http.StatusConflict, cscdm.ZoneEditErr{Code: "ZONE_EXISTS", Description: "zone already exists", Value: req.ZoneName})
+			return
+		}
+		writeJson(w, http.StatusCreated, map[string]string{"zoneName": req.ZoneName})
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "zones/"):
+		f.mu.Lock()
+		_, ok := f.zones[strings.TrimPrefix(path, "zones/")]
+		delete(f.zones, strings.TrimPrefix(path, "zones/"))
+		f.mu.Unlock()
+		if !ok {
+			writeJson(w, http.StatusNotFound, cscdm.ZoneEditErr{Code: "NOT_FOUND", Description: "zone not found"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "zones/"):
+		if f.onZoneRead != nil {
+			f.onZoneRead(strings.TrimPrefix(path, "zones/"))
+		}
+		f.mu.Lock()
+		zone, ok := f.zones[strings.TrimPrefix(path, "zones/")]
+		var body []byte
+		if ok {
+			body, _ = json.Marshal(zone)
+		}
+		f.mu.Unlock()
+		if !ok {
+			writeJson(w, http.StatusNotFound, cscdm.ZoneEditErr{Code: "NOT_FOUND", Description: "zone not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeCsc) handleEdit(w http.ResponseWriter, r *http.Request) {
+	var req cscdm.ZoneEditReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "BAD_REQUEST", Description: err.Error()})
+		return
+	}
+
+	f.mu.Lock()
+	f.edits = append(f.edits, req)
+	f.mu.Unlock()
+
+	if f.onEdit != nil && f.onEdit(w, req) {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	zone, ok := f.zones[req.ZoneName]
+	if !ok {
+		writeJson(w, http.StatusNotFound, cscdm.ZoneEditErr{Code: "NOT_FOUND", Description: "zone not found", Value: req.ZoneName})
+		return
+	}
+
+	for _, edit := range req.Edits {
+		f.apply(zone, edit)
+	}
+
+	f.nextId++
+	writeJson(w, http.StatusCreated, map[string]any{
+		"content": map[string]string{"status": "PENDING"},
+		"links": map[string]string{
+			"self":   fmt.Sprintf("%s/zones/edits/%d", f.URL, f.nextId),
+			"status": fmt.Sprintf("%s/zones/edits/status/%d", f.URL, f.nextId),
+		},
+	})
+}
+
+func (f *fakeCsc) apply(zone *cscdm.Zone, edit cscdm.ZoneEdit) {
+	if edit.RecordType == "SOA" && edit.NewSoa != nil {
+		for field, value := range map[*int64]*int64{
+			&zone.SOA.Refresh: edit.NewSoa.Refresh,
+			&zone.SOA.Retry:   edit.NewSoa.Retry,
+			&zone.SOA.Expire:  edit.NewSoa.Expire,
+			&zone.SOA.TtlMin:  edit.NewSoa.TtlMin,
+		} {
+			if value != nil {
+				*field = *value
+			}
+		}
+		if edit.NewSoa.TechEmail != "" {
+			zone.SOA.TechEmail = edit.NewSoa.TechEmail
+		}
+		zone.SOA.Serial++
+		return
+	}
+
+	records := fakeRecordsByType(zone, edit.RecordType)
+	if records == nil {
+		return
+	}
+
+	switch edit.Action {
+	case "ADD":
+		f.nextId++
+		*records = append(*records, cscdm.ZoneRecord{
+			Id:       fmt.Sprintf("%d", f.nextId),
+			Key:      edit.NewKey,
+			Value:    edit.NewValue,
+			Ttl:      edit.NewTtl,
+			Priority: edit.NewPriority,
+			Weight:   edit.NewWeight,
+			Port:     edit.NewPort,
+			Status:   "ACTIVE",
+			Metadata: edit.NewMetadata,
+		})
+	case "EDIT":
+		for i, record := range *records {
+			if record.Key == edit.CurrentKey && record.Value == edit.CurrentValue {
+				(*records)[i].Key = edit.NewKey
+				(*records)[i].Value = edit.NewValue
+				(*records)[i].Ttl = edit.NewTtl
+				(*records)[i].Priority = edit.NewPriority
+				(*records)[i].Weight = edit.NewWeight
+				(*records)[i].Port = edit.NewPort
+				if edit.NewMetadata != nil {
+					(*records)[i].Metadata = edit.NewMetadata
+				}
+			}
+		}
+	case "PURGE":
+		kept := (*records)[:0]
+		for _, record := range *records {
+			if record.Key != edit.CurrentKey || record.Value != edit.CurrentValue {
+				kept = append(kept, record)
+			}
+		}
+		*records = kept
+	}
+}
+
+func fakeRecordsByType(zone *cscdm.Zone, recordType string) *[]cscdm.ZoneRecord {
+	switch recordType {
+	case "A":
+		return &zone.A
+	case "AAAA":
+		return &zone.AAAA
+	case "CNAME":
+		return &zone.CNAME
+	case "MX":
+		return &zone.MX
+	case "NS":
+		return &zone.NS
+	case "TXT":
+		return &zone.TXT
+	case "SRV":
+		return &zone.SRV
+	case "CAA":
+		return &zone.CAA
+	case "TLSA":
+		return &zone.TLSA
+	default:
+		return nil
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -12,6 +12,10 @@
 package cscdm_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"runtime"
 	"sync"
 	"terraform-provider-cscdm/internal/cscdm"
@@ -217,3 +221,35 @@ func testMultipleStops(t *testing.T) {
 // observable behaviors like goroutine counts and shutdown behavior.
 // Both this integration test and the unit tests use only the public API
 // (Configure/Stop) to validate the internal trigger mechanisms.
+
+func TestClient_EnqueueRacingStopNeverHangs(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	for i := 0; i < 20; i++ {
+		fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+		client := fake.newClient(t)
+
+		errs := make(chan error, 10)
+		for j := 0; j < 10; j++ {
+			go func(j int) {
+				_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+					ZoneName: "example.com",
+					ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: fmt.Sprintf("host%d", j), NewValue: "10.0.0.1"},
+				})
+				errs <- err
+			}(j)
+		}
+		client.Stop()
+
+		for j := 0; j < 10; j++ {
+			select {
+			case err := <-errs:
+				if err != nil && !errors.Is(err, cscdm.ErrClientStopped) {
+					t.Errorf("Expected an action racing Stop to succeed or fail with ErrClientStopped, got: %s", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("An action enqueued around Stop was never answered")
+			}
+		}
+	}
+}
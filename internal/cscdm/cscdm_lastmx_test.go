@@ -0,0 +1,80 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func newMxZone() *cscdm.Zone {
+	return &cscdm.Zone{
+		ZoneName: "example.com",
+		MX: []cscdm.ZoneRecord{
+			{Id: "1", Key: "@", Value: "mx1.example.com", Priority: 10},
+			{Id: "2", Key: "@", Value: "mx2.example.com", Priority: 20},
+			{Id: "3", Key: "mail", Value: "mx1.example.com", Priority: 10},
+		},
+	}
+}
+
+func purgeMx(key string, value string, allow bool) *cscdm.RecordAction {
+	return &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "PURGE", RecordType: "MX", CurrentKey: key, CurrentValue: value, AllowLastRecord: allow},
+	}
+}
+
+func TestClient_ProtectLastMxRejectsLastRecord(t *testing.T) {
+	fake := newFakeCsc(t, newMxZone())
+	client := fake.newClient(t)
+	client.ProtectLastMx = true
+
+	if _, err := client.PerformRecordAction(context.Background(), purgeMx("mail", "mx1.example.com", false)); !errors.Is(err, cscdm.ErrLastMxRecord) {
+		t.Errorf("Expected ErrLastMxRecord removing the last MX record, got %v", err)
+	}
+	if n := len(fake.submittedEdits()); n != 0 {
+		t.Errorf("Expected nothing to be submitted, got %d edits", n)
+	}
+
+	if _, err := client.PerformRecordAction(context.Background(), purgeMx("@", "mx2.example.com", false)); err != nil {
+		t.Errorf("Expected removing one of two MX records to succeed, got %s", err)
+	}
+
+	if _, err := client.PerformRecordAction(context.Background(), purgeMx("mail", "mx1.example.com", true)); err != nil {
+		t.Errorf("Expected AllowLastRecord to remove the last MX record, got %s", err)
+	}
+}
+
+func TestClient_ProtectLastMxCountsWholeBatch(t *testing.T) {
+	fake := newFakeCsc(t, newMxZone())
+	client := fake.newClient(t)
+	client.ProtectLastMx = true
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, value := range []string{"mx1.example.com", "mx2.example.com"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.PerformRecordAction(context.Background(), purgeMx("@", value, false))
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if !errors.Is(err, cscdm.ErrLastMxRecord) {
+			t.Errorf("Expected both removals emptying the apex to fail, got %v", err)
+		}
+	}
+}
+
+func TestClient_LastMxUnprotectedByDefault(t *testing.T) {
+	fake := newFakeCsc(t, newMxZone())
+	client := fake.newClient(t)
+
+	if _, err := client.PerformRecordAction(context.Background(), purgeMx("mail", "mx1.example.com", false)); err != nil {
+		t.Errorf("Expected the last MX record to be removable by default, got %s", err)
+	}
+}
@@ -0,0 +1,63 @@
+package cscdm_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func newLoggingClient(t *testing.T) (*cscdm.Client, string) {
+	t.Helper()
+	t.Cleanup(cscdm.SetWarnOutput(io.Discard))
+
+	logFile := filepath.Join(t.TempDir(), "cscdm.log")
+	client := &cscdm.Client{LogFile: logFile, Synchronous: true}
+	client.Configure("secret-key", "secret-token")
+	t.Cleanup(client.Stop)
+
+	return client, logFile
+}
+
+func TestClient_LogFileRedactsCredentials(t *testing.T) {
+	client, logFile := newLoggingClient(t)
+
+	cscdm.Logf(client, "request failed with apikey %s and token %s", "secret-key", "secret-token")
+
+	contents, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %s", err)
+	}
+
+	if strings.Contains(string(contents), "secret-") {
+		t.Errorf("Expected credentials to be redacted, got %q", contents)
+	}
+	if !strings.Contains(string(contents), "request failed with apikey [REDACTED] and token [REDACTED]") {
+		t.Errorf("Expected the redacted line to be logged, got %q", contents)
+	}
+}
+
+func TestClient_LogFileRotatesWhenFull(t *testing.T) {
+	client, logFile := newLoggingClient(t)
+
+	if err := os.WriteFile(logFile, make([]byte, cscdm.MAX_LOG_FILE_SIZE), 0o600); err != nil {
+		t.Fatalf("Failed to fill log file: %s", err)
+	}
+
+	cscdm.Logf(client, "after rotation")
+
+	rotated, err := os.Stat(logFile + ".1")
+	if err != nil || rotated.Size() != cscdm.MAX_LOG_FILE_SIZE {
+		t.Errorf("Expected the full log to be moved aside, got %v", err)
+	}
+
+	contents, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %s", err)
+	}
+	if !strings.HasSuffix(string(contents), " after rotation\n") || len(contents) > 100 {
+		t.Errorf("Expected a fresh log holding only the new line, got %q", contents)
+	}
+}
@@ -0,0 +1,107 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+// unreachableAddr returns an address nothing is listening on, so connecting
+// to it is refused until a listener is started there.
+func unreachableAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve an address: %s", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	return addr
+}
+
+func newOutageClient(t *testing.T, addr string, window time.Duration) *cscdm.Client {
+	t.Helper()
+
+	client := &cscdm.Client{
+		BaseUrl:           "http://" + addr + "/",
+		PollInterval:      10 * time.Millisecond,
+		FlushIdleDuration: 20 * time.Millisecond,
+		OutageWindow:      window,
+	}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	return client
+}
+
+func addA(key string, value string) *cscdm.RecordAction {
+	return &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: key, NewValue: value},
+	}
+}
+
+func TestClient_OutageWindowHoldsEditsUntilRecovery(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	addr := unreachableAddr(t)
+	client := newOutageClient(t, addr, 5*time.Second)
+
+	// Bring CSC back part way through the window.
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Errorf("Failed to restore CSC: %s", err)
+			return
+		}
+		server := &http.Server{Handler: fake.Config.Handler}
+		t.Cleanup(func() { server.Close() })
+		_ = server.Serve(listener)
+	}()
+
+	start := time.Now()
+	record, err := client.PerformRecordAction(context.Background(), addA("www", "192.0.2.1"))
+	if err != nil {
+		t.Fatalf("Expected the edit to be applied once CSC recovered, got %s", err)
+	}
+	if record == nil || record.Value != "192.0.2.1" {
+		t.Errorf("Expected the added record, got %+v", record)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected the edit to wait out the outage, returned after %s", elapsed)
+	}
+	if n := len(fake.submittedEdits()); n != 1 {
+		t.Errorf("Expected the edit to be submitted once, got %d", n)
+	}
+}
+
+func TestClient_OutageWindowExpires(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	client := newOutageClient(t, unreachableAddr(t), 100*time.Millisecond)
+
+	_, err := client.PerformRecordAction(context.Background(), addA("www", "192.0.2.1"))
+	if !errors.Is(err, cscdm.ErrOutageWindowExceeded) || !errors.Is(err, cscdm.ErrUnreachable) {
+		t.Errorf("Expected ErrOutageWindowExceeded wrapping ErrUnreachable, got %v", err)
+	}
+}
+
+func TestClient_UnreachableFailsWithoutOutageWindow(t *testing.T) {
+	defer cscdm.SetWarnOutput(io.Discard)()
+
+	client := newOutageClient(t, unreachableAddr(t), 0)
+
+	_, err := client.PerformRecordAction(context.Background(), addA("www", "192.0.2.1"))
+	if err == nil || errors.Is(err, cscdm.ErrOutageWindowExceeded) {
+		t.Errorf("Expected the edit to fail straight away, got %v", err)
+	}
+}
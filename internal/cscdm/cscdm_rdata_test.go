@@ -0,0 +1,61 @@
+package cscdm_test
+
+import (
+	"math"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestParseHinfoValue(t *testing.T) {
+	hinfo, err := cscdm.ParseHinfoValue(`"INTEL-386" "Windows NT"`)
+	if err != nil {
+		t.Fatalf("Expected HINFO to parse, got error: %s", err)
+	}
+	if hinfo.Cpu != "INTEL-386" || hinfo.Os != "Windows NT" {
+		t.Errorf("Unexpected HINFO fields: %+v", hinfo)
+	}
+
+	for _, value := range []string{`"INTEL-386"`, `"unterminated os`, `a b c`} {
+		if _, err := cscdm.ParseHinfoValue(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestParseNaptrValue(t *testing.T) {
+	naptr, err := cscdm.ParseNaptrValue(`100 10 "U" "E2U+sip" "!^.*$!sip:info@example.com!" .`)
+	if err != nil {
+		t.Fatalf("Expected NAPTR to parse, got error: %s", err)
+	}
+	if naptr.Order != 100 || naptr.Preference != 10 || naptr.Flags != "U" || naptr.Service != "E2U+sip" ||
+		naptr.Regexp != "!^.*$!sip:info@example.com!" || naptr.Replacement != "." {
+		t.Errorf("Unexpected NAPTR fields: %+v", naptr)
+	}
+
+	for _, value := range []string{`100 10 "U" "E2U+sip" "!^.*$!x!"`, `-1 10 "" "" "" .`, `100 70000 "" "" "" .`} {
+		if _, err := cscdm.ParseNaptrValue(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestParseLocValue(t *testing.T) {
+	loc, err := cscdm.ParseLocValue("42 21 54 N 71 6 18 W -24m 30m")
+	if err != nil {
+		t.Fatalf("Expected LOC to parse, got error: %s", err)
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+	if !near(loc.Latitude, 42.365) || !near(loc.Longitude, -71.105) || loc.Altitude != -24 || loc.Size != 30 {
+		t.Errorf("Unexpected LOC fields: %+v", loc)
+	}
+	if loc.HorizontalPrecision != 10000 || loc.VerticalPrecision != 10 {
+		t.Errorf("Expected RFC 1876 default precisions, got %+v", loc)
+	}
+
+	for _, value := range []string{"42 21 54 71 6 18 W 0m", "91 N 71 W 0m", "42 N 71 W", "42 N 71 W 0m 1m 2m 3m 4m"} {
+		if _, err := cscdm.ParseLocValue(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
@@ -0,0 +1,754 @@
+package cscdm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+func renameAction() *cscdm.RecordAction {
+	return &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{
+			Action:       "EDIT",
+			RecordType:   "A",
+			CurrentKey:   "www",
+			CurrentValue: "10.0.0.1",
+			NewKey:       "web",
+			NewValue:     "10.0.0.1",
+		},
+	}
+}
+
+func TestClient_RenameEditsRecordByDefault(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+	})
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordEdit(context.Background(), renameAction())
+	if err != nil {
+		t.Fatalf("Rename failed: %s", err)
+	}
+
+	if record.Key != "web" {
+		t.Errorf("Expected renamed record key 'web', got '%s'", record.Key)
+	}
+
+	submitted := fake.submittedEdits()
+	if len(submitted) != 1 {
+		t.Fatalf("Expected 1 zone edit request, got %d", len(submitted))
+	}
+
+	edits := submitted[0].Edits
+	if len(edits) != 1 || edits[0].Action != "EDIT" || edits[0].CurrentKey != "www" || edits[0].NewKey != "web" {
+		t.Errorf("Expected a single EDIT of 'www' to 'web', got %+v", edits)
+	}
+}
+
+func TestClient_RenameReplacesRecord(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+	})
+	client := fake.newClient(t)
+	client.RenameStrategy = cscdm.RENAME_STRATEGY_REPLACE
+
+	record, err := client.PerformRecordEdit(context.Background(), renameAction())
+	if err != nil {
+		t.Fatalf("Rename failed: %s", err)
+	}
+
+	if record.Key != "web" {
+		t.Errorf("Expected renamed record key 'web', got '%s'", record.Key)
+	}
+
+	submitted := fake.submittedEdits()
+	if len(submitted) != 1 {
+		t.Fatalf("Expected 1 zone edit request, got %d", len(submitted))
+	}
+
+	edits := submitted[0].Edits
+	if len(edits) != 2 || edits[0].Action != "PURGE" || edits[0].CurrentKey != "www" || edits[1].Action != "ADD" || edits[1].NewKey != "web" {
+		t.Errorf("Expected PURGE of 'www' followed by ADD of 'web', got %+v", edits)
+	}
+}
+
+func TestClient_RenameKeepsRecordWhenAddFails(t *testing.T) {
+	for _, synchronous := range []bool{false, true} {
+		fake := newFakeCsc(t, &cscdm.Zone{
+			ZoneName: "example.com",
+			A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+		})
+		fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+			for _, edit := range req.Edits {
+				if edit.Action == "ADD" {
+					writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "INVALID_RECORD", Description: "record value is invalid"})
+					return true
+				}
+			}
+			return false
+		}
+		client := fake.newClient(t)
+		client.Synchronous = synchronous
+		client.RenameStrategy = cscdm.RENAME_STRATEGY_REPLACE
+
+		if _, err := client.PerformRecordEdit(context.Background(), renameAction()); err == nil {
+			t.Fatalf("synchronous = %t: Expected the rename to fail", synchronous)
+		}
+
+		for _, req := range fake.submittedEdits() {
+			if len(req.Edits) != 2 {
+				t.Errorf("synchronous = %t: Expected the PURGE and ADD in one zone edit, got %+v", synchronous, req.Edits)
+			}
+		}
+
+		zone, err := client.RefreshZone(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("RefreshZone failed: %s", err)
+		}
+		if _, err := client.GetRecordByTypeByKey(zone, "A", "www"); err != nil {
+			t.Errorf("synchronous = %t: Expected 'www' to survive the failed rename: %s", synchronous, err)
+		}
+	}
+}
+
+func TestClient_RenameRejectedWithErrorStrategy(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+	})
+	client := fake.newClient(t)
+	client.RenameStrategy = cscdm.RENAME_STRATEGY_ERROR
+
+	_, err := client.PerformRecordEdit(context.Background(), renameAction())
+	if err == nil || !strings.Contains(err.Error(), "cannot rename") {
+		t.Fatalf("Expected rename to be rejected, got: %v", err)
+	}
+
+	if submitted := fake.submittedEdits(); len(submitted) != 0 {
+		t.Errorf("Expected no zone edit requests, got %d", len(submitted))
+	}
+}
+
+func TestClient_CreateAdoptsRecordWhenResponseLost(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		// Apply the edit, then drop the connection before responding.
+		fake.mu.Lock()
+		for _, edit := range req.Edits {
+			fake.apply(fake.zones[req.ZoneName], edit)
+		}
+		fake.mu.Unlock()
+
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("Response writer does not support hijacking")
+			return false
+		}
+
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("Failed to hijack connection: %s", err)
+			return false
+		}
+		conn.Close()
+		return true
+	}
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected created record to be adopted, got error: %s", err)
+	}
+
+	if record.Key != "www" || record.Id == "" {
+		t.Errorf("Expected adopted record for 'www' with an id, got %+v", record)
+	}
+
+	if submitted := fake.submittedEdits(); len(submitted) != 1 {
+		t.Errorf("Expected exactly 1 zone edit request, got %d", len(submitted))
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if n := len(fake.zones["example.com"].A); n != 1 {
+		t.Errorf("Expected exactly 1 A record in zone, got %d", n)
+	}
+}
+
+func TestClient_NotFoundSentinels(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+	})
+	client := fake.newClient(t)
+
+	if _, err := client.GetZone("missing.com"); !errors.Is(err, cscdm.ErrZoneNotFound) {
+		t.Errorf("Expected ErrZoneNotFound for a missing zone, got: %v", err)
+	}
+
+	zone, err := client.GetZone("example.com")
+	if err != nil {
+		t.Fatalf("Failed to get zone: %s", err)
+	}
+
+	if _, err := client.GetRecordByTypeById(zone, "A", "2"); !errors.Is(err, cscdm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a missing id, got: %v", err)
+	}
+	if _, err := client.GetRecordByTypeByKey(zone, "A", "mail"); !errors.Is(err, cscdm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a missing key, got: %v", err)
+	}
+	if _, err := client.GetRecordByTypeById(zone, "BOGUS", "1"); errors.Is(err, cscdm.ErrRecordNotFound) {
+		t.Errorf("Expected an unsupported type not to be reported as not found, got: %v", err)
+	}
+}
+
+func TestClient_BareNotFoundIsNotZoneNotFound(t *testing.T) {
+	// A 404 without CSC's error body, as a proxy or a wrong API URL gives.
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	if _, err := client.GetZone("example.com"); err == nil || errors.Is(err, cscdm.ErrZoneNotFound) {
+		t.Errorf("Expected a bare 404 on read to fail without ErrZoneNotFound, got: %v", err)
+	}
+	if err := client.DeleteZone(context.Background(), "example.com"); err == nil || errors.Is(err, cscdm.ErrZoneNotFound) {
+		t.Errorf("Expected a bare 404 on delete to fail without ErrZoneNotFound, got: %v", err)
+	}
+}
+
+func TestClient_RetryPolicyDecidesEditRetries(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	unavailable := 1
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		if unavailable == 0 {
+			return false
+		}
+		unavailable--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return true
+	}
+	client := fake.newClient(t)
+
+	var statusCodes []int
+	client.RetryPolicy = func(attempt int, statusCode int, err error) (bool, time.Duration) {
+		statusCodes = append(statusCodes, statusCode)
+		return statusCode == http.StatusServiceUnavailable, time.Millisecond
+	}
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected edit to succeed after a retry, got error: %s", err)
+	}
+	if record.Key != "www" {
+		t.Errorf("Expected record for 'www', got %+v", record)
+	}
+
+	if len(statusCodes) != 1 || statusCodes[0] != http.StatusServiceUnavailable {
+		t.Errorf("Expected the policy to be consulted once for a 503, got %v", statusCodes)
+	}
+	if submitted := fake.submittedEdits(); len(submitted) != 2 {
+		t.Errorf("Expected the rejected zone edit request to be resubmitted once, got %d requests", len(submitted))
+	}
+}
+
+// dropConnection closes the connection behind w without responding, with a
+// reset rather than a clean close when reset is set.
+func dropConnection(t *testing.T, w http.ResponseWriter, reset bool) {
+	t.Helper()
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Errorf("Failed to hijack connection: %s", err)
+		return
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok && reset {
+		_ = tcpConn.SetLinger(0)
+	}
+	conn.Close()
+}
+
+func TestClient_LostResponseAdoptsOnceEditSettles(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		// Apply the edit only after the client has read the zone once and
+		// found it still pending.
+		submittedAt := fake.requestCount()
+		go func() {
+			for fake.requestCount() < submittedAt+2 {
+				time.Sleep(time.Millisecond)
+			}
+			fake.mu.Lock()
+			for _, edit := range req.Edits {
+				fake.apply(fake.zones[req.ZoneName], edit)
+			}
+			fake.mu.Unlock()
+		}()
+
+		dropConnection(t, w, false)
+		return true
+	}
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the record to be adopted once the edit applied, got error: %s", err)
+	}
+	if record.Key != "www" || record.Id == "" {
+		t.Errorf("Expected adopted record for 'www' with an id, got %+v", record)
+	}
+}
+
+func TestClient_LostResponseDoesNotAdoptExistingRecord(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+	})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		dropConnection(t, w, false)
+		return true
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if !errors.Is(err, cscdm.ErrOutcomeUnknown) {
+		t.Errorf("Expected a record present before the edit not to be adopted, got: %v", err)
+	}
+}
+
+func TestClient_ResetConnectionIsNotUnreachable(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		dropConnection(t, w, true)
+		return true
+	}
+	client := fake.newClient(t)
+	client.OutageWindow = time.Minute
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if !errors.Is(err, cscdm.ErrOutcomeUnknown) || errors.Is(err, cscdm.ErrUnreachable) {
+		t.Errorf("Expected a reset after connecting to leave the outcome unknown, got: %v", err)
+	}
+	if submitted := fake.submittedEdits(); len(submitted) != 1 {
+		t.Errorf("Expected the zone edit not to be held and resubmitted, got %d requests", len(submitted))
+	}
+}
+
+func TestClient_EditServerErrorIsNotResubmitted(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		w.WriteHeader(http.StatusBadGateway)
+		return true
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if !errors.Is(err, cscdm.ErrOutcomeUnknown) {
+		t.Errorf("Expected a 502 to leave the outcome unknown, got: %v", err)
+	}
+	if submitted := fake.submittedEdits(); len(submitted) != 1 {
+		t.Errorf("Expected the zone edit not to be resubmitted after a 502, got %d requests", len(submitted))
+	}
+}
+
+func TestClient_RetryPolicyCanRefuseRetries(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return true
+	}
+	client := fake.newClient(t)
+	client.RetryPolicy = func(int, int, error) (bool, time.Duration) { return false, 0 }
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("Expected the 503 to be returned without retrying, got: %v", err)
+	}
+}
+
+func TestClient_EditErrorReportsDetails(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "INVALID_RECORD", Description: "TTL out of range", Value: "5"})
+		return true
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1", NewTtl: 5},
+	})
+
+	var zeErr *cscdm.ZoneEditErr
+	if !errors.As(err, &zeErr) || zeErr.Description != "TTL out of range" || zeErr.Value != "5" {
+		t.Fatalf("Expected the decoded CSC error, got: %v", err)
+	}
+	for _, detail := range []string{"400", "INVALID_RECORD", "TTL out of range"} {
+		if !strings.Contains(err.Error(), detail) {
+			t.Errorf("Expected the error to mention %q, got: %s", detail, err)
+		}
+	}
+}
+
+func TestClient_EditErrorBodyWithSuccessStatus(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	fake.onEdit = func(w http.ResponseWriter, req cscdm.ZoneEditReq) bool {
+		writeJson(w, http.StatusOK, cscdm.ZoneEditErr{Code: "INVALID_RECORD", Description: "record value is invalid", Value: "10.0.0.1"})
+		return true
+	}
+	client := fake.newClient(t)
+
+	_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "10.0.0.1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "INVALID_RECORD") {
+		t.Fatalf("Expected the error body to be reported despite the 200 status, got: %v", err)
+	}
+}
+
+func TestClient_SkipRefetchSynthesizesRecord(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	add := func(key string, skipRefetch bool) *cscdm.ZoneRecord {
+		t.Helper()
+
+		record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+			ZoneName: "example.com",
+			ZoneEdit: cscdm.ZoneEdit{
+				Action:      "ADD",
+				RecordType:  "MX",
+				NewKey:      key,
+				NewValue:    "mail.example.com",
+				NewTtl:      300,
+				NewPriority: 10,
+				SkipRefetch: skipRefetch,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Add of %s failed: %s", key, err)
+		}
+		return record
+	}
+
+	fetched := add("@", false)
+	synthesized := add("sub", true)
+
+	if synthesized.Id == "" || synthesized.Id == fetched.Id {
+		t.Errorf("Expected the synthesized record to carry its own server-assigned id, got %q (fetched %q)", synthesized.Id, fetched.Id)
+	}
+	if synthesized.Key != "sub" {
+		t.Errorf("Expected synthesized key 'sub', got %q", synthesized.Key)
+	}
+
+	fetched.Id, fetched.Key = synthesized.Id, synthesized.Key
+	if !reflect.DeepEqual(*fetched, *synthesized) {
+		t.Errorf("Expected synthesized record to match the fetched one, got %+v and %+v", *synthesized, *fetched)
+	}
+}
+
+func TestClient_RejectsApexNsChanges(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	ns := func(action string, key string) error {
+		t.Helper()
+
+		edit := cscdm.ZoneEdit{Action: action, RecordType: "NS"}
+		if action == "ADD" {
+			edit.NewKey, edit.NewValue = key, "ns1.example.net"
+		} else {
+			edit.CurrentKey, edit.CurrentValue = key, "ns1.example.net"
+		}
+
+		_, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{ZoneName: "example.com", ZoneEdit: edit})
+		return err
+	}
+
+	for _, key := range []string{"@", "", "example.com", "EXAMPLE.COM."} {
+		if err := ns("ADD", key); !errors.Is(err, cscdm.ErrApexNs) {
+			t.Errorf("Expected ErrApexNs adding NS at apex key %q, got: %v", key, err)
+		}
+	}
+	if err := ns("PURGE", "@"); !errors.Is(err, cscdm.ErrApexNs) {
+		t.Errorf("Expected ErrApexNs purging an apex NS record, got: %v", err)
+	}
+	if edits := fake.submittedEdits(); len(edits) != 0 {
+		t.Errorf("Expected no apex NS edits to reach CSC, got %d", len(edits))
+	}
+
+	if err := ns("ADD", "sub"); err != nil {
+		t.Errorf("Expected a subdomain NS delegation to be allowed, got: %s", err)
+	}
+}
+
+func TestClient_WaitForPropagationUntilSynced(t *testing.T) {
+	zone := &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1", PropagationStatus: "PENDING"}},
+	}
+	fake := newFakeCsc(t, zone)
+	client := fake.newClient(t)
+
+	record, err := client.ReadRecordById(context.Background(), "example.com", "A", "1")
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if record.PropagationStatus != "PENDING" {
+		t.Errorf("Expected propagation status PENDING to be decoded, got %q", record.PropagationStatus)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		fake.mu.Lock()
+		zone.A[0].PropagationStatus = "SYNCED"
+		fake.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record, err = client.WaitForPropagation(ctx, "example.com", "A", "1")
+	if err != nil {
+		t.Fatalf("Wait failed: %s", err)
+	}
+	if record.PropagationStatus != "SYNCED" {
+		t.Errorf("Expected the wait to end on SYNCED, got %q", record.PropagationStatus)
+	}
+}
+
+// lowercaseTransform lowercases values it encodes, which cannot be undone.
+type lowercaseTransform struct{}
+
+func (lowercaseTransform) Encode(recordType string, value string) string {
+	return strings.ToLower(value)
+}
+
+func (lowercaseTransform) Decode(recordType string, value string) (string, bool) {
+	return value, true
+}
+
+func TestClient_ValueTransformRoundTrips(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+		TXT: []cscdm.ZoneRecord{
+			{Id: "2", Key: "old", Value: "env=prod;v1"},
+			{Id: "3", Key: "other", Value: "unmanaged"},
+		},
+	})
+	client := fake.newClient(t)
+	client.ValueTransform = cscdm.AffixTransform{Prefix: "env=prod;", RecordTypes: []string{"TXT"}}
+
+	zone, err := client.GetZone("example.com")
+	if err != nil {
+		t.Fatalf("GetZone failed: %s", err)
+	}
+	if zone.TXT[0].Value != "v1" || zone.TXT[1].Value != "unmanaged" {
+		t.Errorf("Expected TXT values v1 and unmanaged, got %q and %q", zone.TXT[0].Value, zone.TXT[1].Value)
+	}
+	if zone.A[0].Value != "10.0.0.1" {
+		t.Errorf("Expected A values to be left alone, got %q", zone.A[0].Value)
+	}
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "EDIT", RecordType: "TXT", CurrentKey: "old", CurrentValue: "v1", NewKey: "old", NewValue: "v2"},
+	})
+	if err != nil {
+		t.Fatalf("Edit failed: %s", err)
+	}
+	if record.Value != "v2" {
+		t.Errorf("Expected the edited record to read back as v2, got %q", record.Value)
+	}
+
+	submitted := fake.submittedEdits()
+	if len(submitted) != 1 || len(submitted[0].Edits) != 1 {
+		t.Fatalf("Expected a single edit, got %+v", submitted)
+	}
+	if edit := submitted[0].Edits[0]; edit.CurrentValue != "env=prod;v1" || edit.NewValue != "env=prod;v2" {
+		t.Errorf("Expected encoded values to be sent, got %q -> %q", edit.CurrentValue, edit.NewValue)
+	}
+
+	client.ValueTransform = lowercaseTransform{}
+	_, err = client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "TXT", NewKey: "new", NewValue: "Mixed"},
+	})
+	if !errors.Is(err, cscdm.ErrAsymmetricValueTransform) {
+		t.Errorf("Expected ErrAsymmetricValueTransform, got %v", err)
+	}
+}
+
+func TestRecordValuesEqual_Ipv6Forms(t *testing.T) {
+	equivalent := []string{
+		"2001:db8::1",
+		"2001:0db8:0000:0000:0000:0000:0000:0001",
+		"2001:db8:0:0:0:0:0:1",
+		"2001:DB8::0:1",
+		"2001:db8:0::1",
+	}
+	for _, a := range equivalent {
+		for _, b := range equivalent {
+			if !cscdm.RecordValuesEqual("AAAA", a, b) {
+				t.Errorf("Expected %q and %q to be equal", a, b)
+			}
+		}
+	}
+
+	unequal := [][2]string{
+		{"2001:db8::1", "2001:db8::2"},
+		{"2001:db8::1", "not an address"},
+		{"::ffff:10.0.0.1", "2001:db8::1"},
+	}
+	for _, pair := range unequal {
+		if cscdm.RecordValuesEqual("AAAA", pair[0], pair[1]) {
+			t.Errorf("Expected %q and %q to differ", pair[0], pair[1])
+		}
+	}
+
+	if cscdm.RecordValuesEqual("TXT", "2001:db8::1", "2001:0db8::1") {
+		t.Errorf("Expected values of other types to be compared exactly")
+	}
+}
+
+func TestClient_FindsAaaaRecordInEquivalentForm(t *testing.T) {
+	client := &cscdm.Client{}
+	zone := &cscdm.Zone{
+		ZoneName: "example.com",
+		AAAA:     []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "2001:db8::1"}},
+	}
+
+	record, err := client.GetRecordByTypeByKeyValue(zone, "AAAA", "www", "2001:0db8:0000:0000:0000:0000:0000:0001")
+	if err != nil {
+		t.Fatalf("Expected the record to be found, got %s", err)
+	}
+	if record.Id != "1" {
+		t.Errorf("Expected record 1, got %+v", record)
+	}
+}
+
+func TestClient_MetadataRoundTrips(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	metadata := map[string]string{"owner": "platform", "cost-center": "1234"}
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "192.0.2.1", NewMetadata: metadata},
+	})
+	if err != nil {
+		t.Fatalf("Expected the record to be created, got %s", err)
+	}
+
+	if submitted := fake.submittedEdits(); len(submitted) != 1 || !reflect.DeepEqual(submitted[0].Edits[0].NewMetadata, metadata) {
+		t.Errorf("Expected the metadata to be sent to CSC, got %+v", submitted)
+	}
+	if !reflect.DeepEqual(record.Metadata, metadata) {
+		t.Errorf("Expected the metadata to be read back, got %v", record.Metadata)
+	}
+}
+
+func TestZoneEdit_MarshalZeroTtl(t *testing.T) {
+	for _, test := range []struct {
+		edit cscdm.ZoneEdit
+		want bool
+	}{
+		{cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "@", NewValue: "192.0.2.1"}, false},
+		{cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "@", NewValue: "192.0.2.1", ZeroTtl: true}, true},
+		{cscdm.ZoneEdit{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "192.0.2.1", NewTtl: 300, ZeroTtl: true}, true},
+	} {
+		body, err := json.Marshal(test.edit)
+		if err != nil {
+			t.Fatalf("Failed to marshal edit: %s", err)
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatalf("Failed to unmarshal edit: %s", err)
+		}
+		if _, ok := fields["newTtl"]; ok != test.want {
+			t.Errorf("Expected newTtl present=%t in %s", test.want, body)
+		}
+		if _, ok := fields["ZeroTtl"]; ok {
+			t.Errorf("Expected ZeroTtl to stay out of the request, got %s", body)
+		}
+	}
+}
+
+func TestClient_AddSrvRecord(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	record, err := client.PerformRecordAction(context.Background(), &cscdm.RecordAction{
+		ZoneName: "example.com",
+		ZoneEdit: cscdm.ZoneEdit{
+			Action:      "ADD",
+			RecordType:  "SRV",
+			NewKey:      "_sip._tcp",
+			NewValue:    "sip.example.com",
+			NewPriority: 10,
+			NewWeight:   5,
+			NewPort:     5060,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	if record.Key != "_sip._tcp" || record.Priority != 10 || record.Weight != 5 || record.Port != 5060 {
+		t.Errorf("Expected the SRV fields to be read back, got %+v", record)
+	}
+
+	edit := fake.submittedEdits()[0].Edits[0]
+	if edit.NewWeight != 5 || edit.NewPort != 5060 {
+		t.Errorf("Expected weight and port to be sent, got %+v", edit)
+	}
+}
+
+func TestClient_GetRecordOfTypeWithNoRecords(t *testing.T) {
+	client := &cscdm.Client{}
+	zone := &cscdm.Zone{ZoneName: "example.com"}
+
+	if _, err := client.GetRecordByTypeById(zone, "MX", "42"); !errors.Is(err, cscdm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a type the zone has no records of, got %v", err)
+	}
+	if _, err := client.GetRecordByTypeById(zone, "SPF", "42"); err == nil || errors.Is(err, cscdm.ErrRecordNotFound) {
+		t.Errorf("Expected an unsupported type to be rejected, got %v", err)
+	}
+}
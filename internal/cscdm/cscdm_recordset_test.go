@@ -0,0 +1,103 @@
+package cscdm_test
+
+import (
+	"context"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestDiffRecordSet_KeepsUnchangedRecords(t *testing.T) {
+	current := []cscdm.ZoneRecord{
+		{Id: "1", Key: "www", Value: "10.0.0.1", Ttl: 300},
+		{Id: "2", Key: "www", Value: "10.0.0.2", Ttl: 300},
+		{Id: "3", Key: "www", Value: "10.0.0.3", Ttl: 300},
+	}
+	desired := []cscdm.ZoneRecord{
+		{Key: "www", Value: "10.0.0.1", Ttl: 300},
+		{Key: "www", Value: "10.0.0.2", Ttl: 600},
+		{Key: "www", Value: "10.0.0.4", Ttl: 300},
+	}
+
+	edits := cscdm.DiffRecordSet("A", current, desired)
+
+	expected := []struct{ action, value string }{
+		{"PURGE", "10.0.0.3"},
+		{"EDIT", "10.0.0.2"},
+		{"ADD", "10.0.0.4"},
+	}
+	if len(edits) != len(expected) {
+		t.Fatalf("Expected %d edits, got %+v", len(expected), edits)
+	}
+	for i, want := range expected {
+		if edits[i].Action != want.action || edits[i].ValueId() != want.value {
+			t.Errorf("Expected edit %d to be %s of %s, got %+v", i, want.action, want.value, edits[i])
+		}
+	}
+	if edits[1].NewTtl != 600 {
+		t.Errorf("Expected the TTL change to be edited in place, got %+v", edits[1])
+	}
+}
+
+func TestDiffRecordSet_NoChanges(t *testing.T) {
+	current := []cscdm.ZoneRecord{{Id: "1", Key: "@", Value: "mail.example.com", Ttl: 300, Priority: 10}}
+	desired := []cscdm.ZoneRecord{{Key: "@", Value: "mail.example.com", Priority: 10}}
+
+	if edits := cscdm.DiffRecordSet("MX", current, desired); len(edits) != 0 {
+		t.Errorf("Expected no edits for an unchanged set, got %+v", edits)
+	}
+}
+
+func TestDiffRecordSet_MatchesEquivalentValues(t *testing.T) {
+	current := []cscdm.ZoneRecord{
+		{Id: "1", Key: "www", Value: "2001:db8::1", Ttl: 300},
+		{Id: "2", Key: "www", Value: "2001:db8::2", Ttl: 300},
+	}
+	desired := []cscdm.ZoneRecord{
+		{Key: "www", Value: "2001:0db8:0000:0000:0000:0000:0000:0001", Ttl: 300},
+		{Key: "www", Value: "2001:DB8::2", Ttl: 600},
+	}
+
+	edits := cscdm.DiffRecordSet("AAAA", current, desired)
+	if len(edits) != 1 || edits[0].Action != "EDIT" || edits[0].CurrentValue != "2001:db8::2" || edits[0].NewTtl != 600 {
+		t.Errorf("Expected only the TTL of the second address to be edited, got %+v", edits)
+	}
+}
+
+func TestClient_ReconcileRecordSetPreservesIds(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A: []cscdm.ZoneRecord{
+			{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300},
+			{Id: "102", Key: "www", Value: "10.0.0.2", Ttl: 300},
+			{Id: "103", Key: "www", Value: "10.0.0.3", Ttl: 300},
+			{Id: "104", Key: "api", Value: "10.0.0.9", Ttl: 300},
+		},
+	})
+	client := fake.newClient(t)
+
+	records, err := client.ReconcileRecordSet(context.Background(), "example.com", "A", "www", []cscdm.ZoneRecord{
+		{Key: "www", Value: "10.0.0.1", Ttl: 300},
+		{Key: "www", Value: "10.0.0.2", Ttl: 600},
+		{Key: "www", Value: "10.0.0.4", Ttl: 300},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %s", err)
+	}
+
+	if len(records) != 3 || records[0].Id != "101" || records[1].Id != "102" || records[1].Ttl != 600 || records[2].Value != "10.0.0.4" {
+		t.Errorf("Expected unchanged and edited records to keep their ids, got %+v", records)
+	}
+
+	submitted := fake.submittedEdits()
+	if len(submitted) != 1 {
+		t.Fatalf("Expected the set to be reconciled in 1 zone edit request, got %d", len(submitted))
+	}
+	for _, edit := range submitted[0].Edits {
+		if edit.ValueId() == "10.0.0.1" || (edit.Action != "EDIT" && edit.ValueId() == "10.0.0.2") {
+			t.Errorf("Expected unchanged entries not to be re-created, got %+v", edit)
+		}
+		if edit.KeyId() == "api" {
+			t.Errorf("Expected records outside the set to be left alone, got %+v", edit)
+		}
+	}
+}
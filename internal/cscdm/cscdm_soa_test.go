@@ -0,0 +1,98 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestSoaEmail_ConvertsBetweenNotations(t *testing.T) {
+	tests := []struct {
+		dns     string
+		mailbox string
+	}{
+		{"hostmaster.example.com", "hostmaster@example.com"},
+		{`host\.master.example.com`, "host.master@example.com"},
+		{"dns.sub.example.co.uk", "dns@sub.example.co.uk"},
+	}
+
+	for _, test := range tests {
+		mailbox, err := cscdm.SoaEmailToMailbox(test.dns + ".")
+		if err != nil || mailbox != test.mailbox {
+			t.Errorf("SoaEmailToMailbox(%q) = %q, %v; want %q", test.dns+".", mailbox, err, test.mailbox)
+		}
+
+		for _, input := range []string{test.mailbox, test.dns, test.dns + "."} {
+			dns, err := cscdm.MailboxToSoaEmail(input)
+			if err != nil || dns != test.dns {
+				t.Errorf("MailboxToSoaEmail(%q) = %q, %v; want %q", input, dns, err, test.dns)
+			}
+		}
+
+		if !cscdm.SoaEmailsEqual(test.dns+".", test.mailbox) {
+			t.Errorf("Expected %q and %q to name the same mailbox", test.dns, test.mailbox)
+		}
+	}
+}
+
+func TestSoaEmail_RejectsMalformedNames(t *testing.T) {
+	for _, input := range []string{"", "hostmaster", "hostmaster.", "@example.com", "hostmaster@", "host master@example.com", "a@b@example.com", "hostmaster@example..com"} {
+		if dns, err := cscdm.MailboxToSoaEmail(input); err == nil {
+			t.Errorf("Expected MailboxToSoaEmail(%q) to fail, got %q", input, dns)
+		}
+	}
+
+	if cscdm.SoaEmailsEqual("hostmaster.example.com", "admin@example.com") {
+		t.Errorf("Expected different mailboxes not to be equal")
+	}
+	if !cscdm.SoaEmailsEqual("hostmaster.EXAMPLE.com", "hostmaster@example.com") {
+		t.Errorf("Expected domains to compare case-insensitively")
+	}
+}
+
+func TestClient_PerformSoaEdit(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		SOA:      cscdm.ZoneSoaRecord{Serial: 1, Refresh: 3600, Retry: 600, Expire: 1209600, TtlMin: 300},
+	})
+	client := fake.newClient(t)
+
+	retry := int64(900)
+	soa, err := client.PerformSoaEdit(context.Background(), "example.com", cscdm.ZoneSoaEdit{
+		Retry:     &retry,
+		TechEmail: "host.master@example.com",
+	})
+	if err != nil {
+		t.Fatalf("SOA edit failed: %s", err)
+	}
+
+	if soa.Retry != 900 || soa.Refresh != 3600 || soa.Serial != 2 {
+		t.Errorf("Expected only retry to change, got %+v", soa)
+	}
+	if soa.TechEmail != `host\.master.example.com` {
+		t.Errorf("Expected the tech email to be sent in DNS notation, got %q", soa.TechEmail)
+	}
+
+	edit := fake.submittedEdits()[0].Edits[0]
+	if edit.RecordType != "SOA" || edit.Action != "EDIT" || edit.NewSoa.Refresh != nil {
+		t.Errorf("Unexpected SOA edit %+v", edit)
+	}
+}
+
+func TestClient_ClaimSoa(t *testing.T) {
+	client := &cscdm.Client{Synchronous: true}
+	client.Configure("test-key", "test-token")
+
+	if err := client.ClaimSoa("example.com"); err != nil {
+		t.Fatalf("First claim failed: %s", err)
+	}
+	if err := client.ClaimSoa("Example.com."); !errors.Is(err, cscdm.ErrSoaClaimed) {
+		t.Errorf("Expected a second claim to fail with ErrSoaClaimed, got %v", err)
+	}
+
+	client.ReleaseSoa("example.com")
+	if err := client.ClaimSoa("example.com"); err != nil {
+		t.Errorf("Expected a released SOA to be claimable, got %s", err)
+	}
+}
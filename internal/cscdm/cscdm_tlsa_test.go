@@ -0,0 +1,34 @@
+package cscdm_test
+
+import (
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestParseTlsaValue(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"3 1 1 ABCDEF0123", true},
+		{"0 0 0 00", true},
+		{"4 1 1 abcdef", false},
+		{"3 2 1 abcdef", false},
+		{"3 1 3 abcdef", false},
+		{"3 1 1 not-hex", false},
+		{"3 1 abcdef", false},
+	}
+
+	for _, test := range tests {
+		tlsa, err := cscdm.ParseTlsaValue(test.value)
+		if test.valid && err != nil {
+			t.Errorf("Expected %q to parse, got error: %s", test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("Expected %q to be rejected", test.value)
+		}
+		if test.valid && err == nil && tlsa.String() == "" {
+			t.Errorf("Expected %q to format back to a value", test.value)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cscdm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+)
+
+func TestClient_ValidateZoneEditsChecksLocallyWithoutPath(t *testing.T) {
+	fake := newFakeCsc(t, &cscdm.Zone{ZoneName: "example.com"})
+	client := fake.newClient(t)
+
+	req := cscdm.ZoneEditReq{
+		ZoneName: "example.com",
+		Edits: []cscdm.ZoneEdit{
+			{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "192.0.2.1"},
+			{Action: "ADD", RecordType: "A", NewKey: "bad", NewValue: "2001:db8::1"},
+			{Action: "ADD", RecordType: "CNAME", NewKey: "@", NewValue: "target.example.net"},
+		},
+	}
+
+	results, err := client.ValidateZoneEdits(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected local validation to succeed, got %s", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("Expected a valid A record to pass, got %s", results[0].Err)
+	}
+	for _, i := range []int{1, 2} {
+		if !errors.Is(results[i].Err, cscdm.ErrInvalidZoneEdit) {
+			t.Errorf("Expected edit %d to fail with ErrInvalidZoneEdit, got %v", i, results[i].Err)
+		}
+	}
+	if n := fake.requestCount(); n != 0 {
+		t.Errorf("Expected no request without an EditValidatePath, got %d", n)
+	}
+}
+
+func TestClient_ValidateZoneEditsUnsuccessfulStatusKeepsLocalVerdicts(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusInternalServerError} {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+
+		client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true, EditValidatePath: "zones/edits/validate"}
+		client.Configure("test-key", "test-token")
+
+		req := cscdm.ZoneEditReq{
+			ZoneName: "example.com",
+			Edits: []cscdm.ZoneEdit{
+				{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "192.0.2.1"},
+				{Action: "ADD", RecordType: "A", NewKey: "bad", NewValue: "2001:db8::1"},
+			},
+		}
+
+		results, err := client.ValidateZoneEdits(context.Background(), req)
+		if err == nil {
+			t.Errorf("status %d: Expected the failed remote check to be reported", status)
+		}
+		if len(results) != 2 || results[0].Err != nil || !errors.Is(results[1].Err, cscdm.ErrInvalidZoneEdit) {
+			t.Errorf("status %d: Expected the local verdicts alongside the error, got %+v", status, results)
+		}
+
+		// Only a missing endpoint is remembered.
+		client.ValidateZoneEdits(context.Background(), req)
+		if want := map[int]int{http.StatusNotFound: 1, http.StatusInternalServerError: 2}[status]; requests != want {
+			t.Errorf("status %d: Expected %d validation request(s), got %d", status, want, requests)
+		}
+	}
+}
+
+func TestClient_ValidateZoneEditsReportsRemoteVerdicts(t *testing.T) {
+	var submitted cscdm.ZoneEditReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/zones/edits/validate" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&submitted); err != nil {
+			writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "BAD_REQUEST", Description: err.Error()})
+			return
+		}
+		writeJson(w, http.StatusOK, map[string]any{"edits": []map[string]any{
+			{"valid": true},
+			{"valid": false, "code": "INVALID_RECORD", "description": "record is invalid"},
+		}})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", Synchronous: true, EditValidatePath: "zones/edits/validate"}
+	client.Configure("test-key", "test-token")
+
+	results, err := client.ValidateZoneEdits(context.Background(), cscdm.ZoneEditReq{
+		ZoneName: "example.com",
+		Edits: []cscdm.ZoneEdit{
+			{Action: "ADD", RecordType: "A", NewKey: "bad", NewValue: "not-an-ip"},
+			{Action: "ADD", RecordType: "A", NewKey: "www", NewValue: "192.0.2.1"},
+			{Action: "ADD", RecordType: "A", NewKey: "dup", NewValue: "192.0.2.2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected validation to succeed, got %s", err)
+	}
+
+	if n := len(submitted.Edits); n != 2 {
+		t.Errorf("Expected only the 2 locally valid edits to be sent to CSC, got %d", n)
+	}
+	if !errors.Is(results[0].Err, cscdm.ErrInvalidZoneEdit) {
+		t.Errorf("Expected the local check to reject edit 0, got %v", results[0].Err)
+	}
+	if results[1].Err != nil {
+		t.Errorf("Expected edit 1 to be valid, got %s", results[1].Err)
+	}
+	var zeErr *cscdm.ZoneEditErr
+	if !errors.As(results[2].Err, &zeErr) || zeErr.Code != "INVALID_RECORD" {
+		t.Errorf("Expected the endpoint's INVALID_RECORD verdict for edit 2, got %v", results[2].Err)
+	}
+}
+
+func TestCheckCnameExclusivity(t *testing.T) {
+	edits := []cscdm.ZoneEdit{
+		{Action: "ADD", RecordType: "CNAME", NewKey: "www", NewValue: "target.example.net"},
+		{Action: "ADD", RecordType: "TXT", NewKey: "WWW.", NewValue: "hello"},
+		{Action: "ADD", RecordType: "A", NewKey: "api", NewValue: "192.0.2.1"},
+		{Action: "ADD", RecordType: "A", NewKey: "api", NewValue: "192.0.2.2"},
+		{Action: "ADD", RecordType: "CNAME", NewKey: "docs", NewValue: "target.example.net"},
+		{Action: "PURGE", RecordType: "A", CurrentKey: "docs", CurrentValue: "192.0.2.3"},
+	}
+
+	errs := cscdm.CheckCnameExclusivity("example.com", edits)
+
+	for _, i := range []int{0, 1} {
+		if !errors.Is(errs[i], cscdm.ErrCnameConflict) {
+			t.Errorf("Expected edit %d to conflict, got %v", i, errs[i])
+		}
+	}
+	if errs[0] == nil || !strings.Contains(errs[0].Error(), `TXT 'WWW.' "hello"`) {
+		t.Errorf("Expected the conflict to name the TXT record, got %v", errs[0])
+	}
+	for _, i := range []int{2, 3, 4, 5} {
+		if errs[i] != nil {
+			t.Errorf("Expected edit %d to be valid, got %s", i, errs[i])
+		}
+	}
+}
+
+func TestCheckCnameExclusivity_ApexForms(t *testing.T) {
+	edits := []cscdm.ZoneEdit{
+		{Action: "ADD", RecordType: "CNAME", NewKey: "example.com.", NewValue: "target.example.net"},
+		{Action: "ADD", RecordType: "MX", NewKey: "@", NewValue: "mail.example.com"},
+	}
+
+	for i, err := range cscdm.CheckCnameExclusivity("example.com", edits) {
+		if !errors.Is(err, cscdm.ErrCnameConflict) {
+			t.Errorf("Expected edit %d at the apex to conflict, got %v", i, err)
+		}
+	}
+}
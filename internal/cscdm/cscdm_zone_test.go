@@ -0,0 +1,338 @@
+package cscdm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-cscdm/internal/cscdm"
+	"testing"
+	"time"
+)
+
+func TestClient_FetchZoneFollowsPagination(t *testing.T) {
+	pages := map[string]any{
+		"": map[string]any{
+			"zoneName": "example.com",
+			"meta":     map[string]int{"pages": 3},
+			"a":        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+			"mx":       []cscdm.ZoneRecord{{Id: "2", Key: "@", Value: "mail.example.com", Priority: 10}},
+		},
+		"2": map[string]any{
+			"zoneName": "example.com",
+			"meta":     map[string]int{"pages": 3},
+			"a":        []cscdm.ZoneRecord{{Id: "3", Key: "api", Value: "10.0.0.2"}},
+		},
+		"3": map[string]any{
+			"zoneName": "example.com",
+			"meta":     map[string]int{"pages": 3},
+			"txt":      []cscdm.ZoneRecord{{Id: "4", Key: "@", Value: "v=spf1 -all"}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones/example.com" {
+			http.NotFound(w, r)
+			return
+		}
+
+		page, ok := pages[r.URL.Query().Get("page")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJson(w, http.StatusOK, page)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	zone, err := client.FetchZone(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Failed to fetch zone: %s", err)
+	}
+
+	if len(zone.A) != 2 || len(zone.MX) != 1 || len(zone.TXT) != 1 {
+		t.Fatalf("Expected records from all pages, got %d A, %d MX, %d TXT", len(zone.A), len(zone.MX), len(zone.TXT))
+	}
+
+	if _, err := client.GetRecordByTypeByKey(zone, "A", "api"); err != nil {
+		t.Errorf("Expected record from page 2 to be found: %s", err)
+	}
+}
+
+func TestClient_FetchZoneRejectsUnsuccessfulStatus(t *testing.T) {
+	var requests int
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeJson(w, status, cscdm.ZoneEditErr{Code: "INVALID_VALUE", Description: "Malformed zone name", Value: "example..com"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	_, err := client.GetZone("example..com")
+	var zeErr *cscdm.ZoneEditErr
+	if !errors.As(err, &zeErr) || zeErr.Code != "INVALID_VALUE" || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("Expected the CSC error and status code, got %v", err)
+	}
+
+	status = http.StatusForbidden
+	if _, err := client.GetZone("example..com"); !errors.Is(err, cscdm.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the failed read not to be cached, got %d requests", requests)
+	}
+}
+
+func TestClient_ZoneCacheExpires(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Hold the response so concurrent reads overlap.
+		time.Sleep(20 * time.Millisecond)
+		writeJson(w, http.StatusOK, map[string]any{"zoneName": "example.com"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", ZoneCacheTtl: 100 * time.Millisecond}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetZone("example.com"); err != nil {
+			t.Fatalf("GetZone failed: %s", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("Expected the second read to be served from the cache, got %d requests", n)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetZone("example.com"); err != nil {
+				t.Errorf("GetZone failed: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected the expired zone to be read again exactly once, got %d requests", n)
+	}
+}
+
+func TestClient_DisableCacheReadsEveryTime(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Hold the response so concurrent reads overlap.
+		time.Sleep(20 * time.Millisecond)
+		writeJson(w, http.StatusOK, map[string]any{"zoneName": "example.com", "a": []cscdm.ZoneRecord{}})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", DisableCache: true}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetZone("example.com"); err != nil {
+			t.Fatalf("GetZone failed: %s", err)
+		}
+	}
+	if _, err := client.FetchZoneRecords(context.Background(), "example.com", "A"); err != nil {
+		t.Fatalf("FetchZoneRecords failed: %s", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("Expected every read to reach CSC, got %d requests", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetZone("example.com"); err != nil {
+				t.Errorf("GetZone failed: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := requests.Load(); n != 4 {
+		t.Errorf("Expected concurrent reads to share one request, got %d requests in total", n)
+	}
+}
+
+func TestClient_FetchZoneRecordsRequestsSingleType(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		zone := map[string]any{"zoneName": "example.com"}
+		if r.URL.Query().Get("recordType") == "A" {
+			zone["a"] = []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}}
+		}
+		writeJson(w, http.StatusOK, zone)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	for i := 0; i < 2; i++ {
+		record, err := client.ReadRecordById(context.Background(), "example.com", "A", "1")
+		if err != nil {
+			t.Fatalf("Failed to read record: %s", err)
+		}
+		if record.Key != "www" {
+			t.Errorf("Expected record 'www', got %+v", record)
+		}
+	}
+
+	if len(queries) != 1 || queries[0] != "recordType=A" {
+		t.Errorf("Expected a single request filtered to A records, got %q", queries)
+	}
+}
+
+func TestClient_FetchZoneRecordsFallsBackToFullZone(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		if r.URL.Query().Has("recordType") {
+			writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "BAD_REQUEST", Description: "unknown parameter", Value: "recordType"})
+			return
+		}
+		writeJson(w, http.StatusOK, cscdm.Zone{
+			ZoneName: "example.com",
+			A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}},
+			TXT:      []cscdm.ZoneRecord{{Id: "2", Key: "@", Value: "v=spf1 -all"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	records, err := client.FetchZoneRecords(context.Background(), "example.com", "TXT")
+	if err != nil {
+		t.Fatalf("Failed to fetch records: %s", err)
+	}
+	if len(records) != 1 || records[0].Id != "2" {
+		t.Errorf("Expected only the TXT record, got %+v", records)
+	}
+
+	if _, err := client.FetchZoneRecords(context.Background(), "example.com", "A"); err != nil {
+		t.Fatalf("Failed to fetch records: %s", err)
+	}
+
+	if len(queries) != 2 || queries[1] != "" {
+		t.Errorf("Expected one rejected filtered request then one full read, got %q", queries)
+	}
+}
+
+func TestClient_FetchZoneRecordsReportsOtherBadRequests(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		writeJson(w, http.StatusBadRequest, cscdm.ZoneEditErr{Code: "INVALID_ZONE", Description: "zone name is invalid", Value: "example.com"})
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.FetchZoneRecords(context.Background(), "example.com", "TXT")
+		var zeErr *cscdm.ZoneEditErr
+		if !errors.As(err, &zeErr) || zeErr.Code != "INVALID_ZONE" {
+			t.Errorf("Expected CSC's error to be returned, got: %v", err)
+		}
+	}
+
+	if len(queries) != 2 || queries[0] != "recordType=TXT" || queries[1] != "recordType=TXT" {
+		t.Errorf("Expected the filter to be kept after an unrelated 400, got %q", queries)
+	}
+}
+
+func TestClient_GetZonesKeepsOrderAndJoinsErrors(t *testing.T) {
+	fake := newFakeCsc(t,
+		&cscdm.Zone{ZoneName: "example.com"},
+		&cscdm.Zone{ZoneName: "example.org"},
+	)
+	client := fake.newClient(t)
+
+	zones, err := client.GetZones(context.Background(), []string{"example.org", "example.com"}, false)
+	if err != nil {
+		t.Fatalf("GetZones failed: %s", err)
+	}
+	if len(zones) != 2 || zones[0].ZoneName != "example.org" || zones[1].ZoneName != "example.com" {
+		t.Fatalf("Expected zones in the order requested, got %+v", zones)
+	}
+
+	requests := fake.requestCount()
+	if _, err := client.GetZones(context.Background(), []string{"example.com"}, false); err != nil {
+		t.Fatalf("GetZones failed: %s", err)
+	}
+	if n := fake.requestCount(); n != requests {
+		t.Errorf("Expected cached zones to be reused, got %d more requests", n-requests)
+	}
+	if _, err := client.GetZones(context.Background(), []string{"example.com"}, true); err != nil {
+		t.Fatalf("GetZones failed: %s", err)
+	}
+	if n := fake.requestCount(); n != requests+1 {
+		t.Errorf("Expected a refresh to read the zone live, got %d more requests", n-requests)
+	}
+
+	_, err = client.GetZones(context.Background(), []string{"example.com", "missing.com"}, false)
+	if !errors.Is(err, cscdm.ErrZoneNotFound) || !strings.Contains(err.Error(), "missing.com") {
+		t.Errorf("Expected ErrZoneNotFound naming the missing zone, got %v", err)
+	}
+}
+
+func TestClient_CreateAndDeleteZone(t *testing.T) {
+	fake := newFakeCsc(t)
+	client := fake.newClient(t)
+	ctx := context.Background()
+
+	zone, err := client.CreateZone(ctx, "example.com", "MANAGED")
+	if err != nil {
+		t.Fatalf("Create failed: %s", err)
+	}
+	if zone.HostingType != "MANAGED" || zone.SOA.Serial != 1 {
+		t.Errorf("Expected the created zone to be read back, got %+v", zone)
+	}
+
+	if _, err := client.CreateZone(ctx, "example.com", ""); err == nil || !strings.Contains(err.Error(), "ZONE_EXISTS") {
+		t.Errorf("Expected creating an existing zone to report CSC's error, got %v", err)
+	}
+
+	if err := client.DeleteZone(ctx, "example.com"); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if _, err := client.GetZone("example.com"); !errors.Is(err, cscdm.ErrZoneNotFound) {
+		t.Errorf("Expected the deleted zone to be gone from the cache, got %v", err)
+	}
+	if err := client.DeleteZone(ctx, "example.com"); !errors.Is(err, cscdm.ErrZoneNotFound) {
+		t.Errorf("Expected deleting a missing zone to return ErrZoneNotFound, got %v", err)
+	}
+}
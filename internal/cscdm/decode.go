@@ -0,0 +1,80 @@
+package cscdm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalJSON decodes a record, accepting its TTL, priority, weight and
+// port as either JSON numbers or strings so that a change in how CSC
+// serializes them does not break decoding.
+func (r *ZoneRecord) UnmarshalJSON(data []byte) error {
+	type plain ZoneRecord
+	aux := struct {
+		*plain
+		Ttl      json.RawMessage `json:"ttl"`
+		Priority json.RawMessage `json:"priority"`
+		Weight   json.RawMessage `json:"weight"`
+		Port     json.RawMessage `json:"port"`
+	}{plain: (*plain)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if r.Ttl, err = parseJsonInt(aux.Ttl, math.MinInt64, math.MaxInt64); err != nil {
+		return fmt.Errorf("record %s has invalid ttl: %s", r.Id, err)
+	}
+	if r.Priority, err = parseJsonInt(aux.Priority, math.MinInt64, math.MaxInt64); err != nil {
+		return fmt.Errorf("record %s has invalid priority: %s", r.Id, err)
+	}
+	if r.Weight, err = parseJsonInt(aux.Weight, 0, math.MaxUint16); err != nil {
+		return fmt.Errorf("record %s has invalid weight: %s", r.Id, err)
+	}
+	port, err := parseJsonInt(aux.Port, 0, math.MaxUint16)
+	if err != nil {
+		return fmt.Errorf("record %s has invalid port: %s", r.Id, err)
+	}
+	r.Port = int32(port)
+
+	return nil
+}
+
+// parseJsonInt parses a JSON number or string holding a whole number within
+// [min, max]. Missing, null and empty string values are zero.
+func parseJsonInt(raw json.RawMessage, min int64, max int64) (int64, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	text := string(raw)
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return 0, err
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return 0, nil
+		}
+	}
+
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		f, fErr := strconv.ParseFloat(text, 64)
+		if fErr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%s is not a whole number", raw)
+		}
+		n = int64(f)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%s is out of range", raw)
+	}
+
+	return n, nil
+}
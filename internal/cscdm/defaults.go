@@ -0,0 +1,44 @@
+package cscdm
+
+import "strings"
+
+// ZoneDefaults are values applied to records in a zone that omit them. A
+// value set on the record always wins, and a zero default means none.
+type ZoneDefaults struct {
+	// Priority applies to MX and SRV records.
+	Priority int64
+	// Weight applies to SRV records.
+	Weight int64
+}
+
+// DefaultsFor returns the defaults configured for a zone, matching the zone
+// name case-insensitively and ignoring any trailing dot.
+func (c *Client) DefaultsFor(zoneName string) ZoneDefaults {
+	zoneName = strings.TrimSuffix(strings.ToLower(zoneName), ".")
+
+	for name, defaults := range c.ZoneDefaults {
+		if strings.TrimSuffix(strings.ToLower(name), ".") == zoneName {
+			return defaults
+		}
+	}
+
+	return ZoneDefaults{}
+}
+
+// DefaultPriority returns the priority to send for a record that omits one.
+func (c *Client) DefaultPriority(zoneName string, recordType string) int64 {
+	if recordType != "MX" && recordType != "SRV" {
+		return 0
+	}
+
+	return c.DefaultsFor(zoneName).Priority
+}
+
+// DefaultWeight returns the weight to send for a record that omits one.
+func (c *Client) DefaultWeight(zoneName string, recordType string) int64 {
+	if recordType != "SRV" {
+		return 0
+	}
+
+	return c.DefaultsFor(zoneName).Weight
+}
@@ -0,0 +1,171 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// resolvingRecordTypes are the record types that make a name resolve, and so
+// can be the target of a CNAME or MX record.
+var resolvingRecordTypes = []string{"A", "AAAA", "CNAME"}
+
+// PerformRecordPurge performs a PURGE and, when DependencyChecks is enabled,
+// returns descriptions of the CNAME and MX records in the zone that are left
+// pointing at a name the batch removed.
+func (c *Client) PerformRecordPurge(ctx context.Context, payload *RecordAction) ([]string, error) {
+	_, err := c.PerformRecordAction(ctx, payload)
+
+	id := c.genId(payload.ZoneName, payload.RecordType, payload.KeyId(), payload.ValueId())
+
+	c.orphansMutex.Lock()
+	orphans := c.orphans[id]
+	delete(c.orphans, id)
+	c.orphansMutex.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return orphans, nil
+}
+
+// recordOrphans checks a zone's batch for PURGEs that remove the last record
+// at a name still targeted by a CNAME or MX record, and stores the dependents
+// for PerformRecordPurge to report. zone is read by queuedZones before the
+// batch takes batchMutex; a nil zone is skipped, since the checks are
+// advisory.
+func (c *Client) recordOrphans(zoneName string, zone *Zone, edits []ZoneEdit) {
+	if zone == nil {
+		return
+	}
+
+	orphans := c.orphanedRecords(zone, edits)
+	if len(orphans) == 0 {
+		return
+	}
+
+	c.orphansMutex.Lock()
+	defer c.orphansMutex.Unlock()
+
+	for _, edit := range edits {
+		if edit.Action != "PURGE" {
+			continue
+		}
+
+		name := zoneFqdn(zone.ZoneName, edit.CurrentKey)
+		if dependents, ok := orphans[name]; ok {
+			id := c.genId(zoneName, edit.RecordType, edit.KeyId(), edit.ValueId())
+			c.orphans[id] = dependents
+		}
+	}
+}
+
+// orphanedRecords returns, keyed by the name they target, the CNAME and MX
+// records that would point at a name with no A, AAAA or CNAME records left
+// once edits are applied. Records purged by the same edits are not reported.
+func (c *Client) orphanedRecords(zone *Zone, edits []ZoneEdit) map[string][]string {
+	purged := make(map[string]bool)
+	removed := make(map[string]bool)
+	added := make(map[string]bool)
+
+	for _, edit := range edits {
+		switch edit.Action {
+		case "PURGE":
+			purged[edit.RecordType+":"+edit.CurrentKey+":"+edit.CurrentValue] = true
+			if isResolvingRecordType(edit.RecordType) {
+				removed[zoneFqdn(zone.ZoneName, edit.CurrentKey)] = true
+			}
+		case "ADD", "EDIT":
+			if isResolvingRecordType(edit.RecordType) {
+				added[zoneFqdn(zone.ZoneName, edit.NewKey)] = true
+			}
+		}
+	}
+
+	// A name survives the batch if anything still resolves it afterwards.
+	for _, recordType := range resolvingRecordTypes {
+		for _, record := range c.GetRecordsByType(zone, recordType) {
+			if !purged[recordType+":"+record.Key+":"+record.Value] {
+				added[zoneFqdn(zone.ZoneName, record.Key)] = true
+			}
+		}
+	}
+
+	orphans := make(map[string][]string)
+	for _, recordType := range []string{"CNAME", "MX"} {
+		for _, record := range c.GetRecordsByType(zone, recordType) {
+			if purged[recordType+":"+record.Key+":"+record.Value] {
+				continue
+			}
+
+			target := zoneTarget(zone.ZoneName, record.Value)
+			if removed[target] && !added[target] {
+				orphans[target] = append(orphans[target], fmt.Sprintf("%s %s -> %s", recordType, record.Key, record.Value))
+			}
+		}
+	}
+
+	return orphans
+}
+
+// queuedZones reads the zones with actions in the queue, for the dependency
+// checks of the next batch. The reads happen without holding batchMutex, so
+// enqueueing is not held up behind them. Zones that cannot be read are left
+// out.
+func (c *Client) queuedZones() map[string]*Zone {
+	c.batchMutex.Lock()
+	var zoneNames []string
+	for _, recordAction := range c.recordActionQueue {
+		if !slices.Contains(zoneNames, recordAction.ZoneName) {
+			zoneNames = append(zoneNames, recordAction.ZoneName)
+		}
+	}
+	c.batchMutex.Unlock()
+
+	zones := make(map[string]*Zone)
+	for _, zoneName := range zoneNames {
+		if zone, err := c.GetZone(zoneName); err == nil {
+			zones[zoneName] = zone
+		}
+	}
+
+	return zones
+}
+
+func isResolvingRecordType(recordType string) bool {
+	for _, t := range resolvingRecordTypes {
+		if t == recordType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zoneFqdn returns the lowercased fully qualified name of a record key.
+func zoneFqdn(zoneName string, key string) string {
+	if IsApexKey(zoneName, key) {
+		return strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	}
+	if strings.HasSuffix(key, ".") {
+		return strings.ToLower(strings.TrimSuffix(key, "."))
+	}
+
+	return strings.ToLower(key + "." + strings.TrimSuffix(zoneName, "."))
+}
+
+// zoneTarget returns the lowercased fully qualified name a CNAME or MX value
+// points at. Values inside the zone may be given with or without the zone
+// name appended.
+func zoneTarget(zoneName string, value string) string {
+	target := strings.ToLower(strings.TrimSuffix(value, "."))
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+
+	if target == zoneName || strings.HasSuffix(target, "."+zoneName) || strings.HasSuffix(value, ".") {
+		return target
+	}
+
+	return target + "." + zoneName
+}
@@ -0,0 +1,59 @@
+package cscdm
+
+import "errors"
+
+// ErrZoneNotFound is returned when CSC has no zone by the requested name.
+var ErrZoneNotFound = errors.New("zone not found")
+
+// ErrRecordNotFound is returned when a zone holds no matching record.
+var ErrRecordNotFound = errors.New("record not found")
+
+// ErrOutcomeUnknown marks a request that may or may not have been applied
+// by CSC because no response was received.
+var ErrOutcomeUnknown = errors.New("request outcome unknown")
+
+// errRecordTypeFilterUnsupported is returned when CSC rejects a zone read
+// restricted to a single record type with an error naming the recordType
+// parameter.
+var errRecordTypeFilterUnsupported = errors.New("record type filter unsupported")
+
+// ErrUnreachable is returned when CSC could not be contacted at all.
+var ErrUnreachable = errors.New("CSC Domain Manager unreachable")
+
+// ErrUnauthorized is returned when CSC rejects the configured credentials.
+var ErrUnauthorized = errors.New("credentials rejected")
+
+// ErrApexNs is returned when a record action would add or remove one of the
+// zone's apex NS records, which CSC manages as the zone's delegation.
+var ErrApexNs = errors.New("apex NS records are managed by CSC")
+
+// ErrUnsupportedHostingType is returned when a zone's hosting type is not
+// one that records are managed under.
+var ErrUnsupportedHostingType = errors.New("zone hosting type does not support record management")
+
+// ErrClientStopped is returned for record actions queued on a client that
+// was stopped before it could submit them.
+var ErrClientStopped = errors.New("client stopped")
+
+// ErrAsymmetricValueTransform is returned when a ValueTransform does not
+// decode a value it encoded back to the original.
+var ErrAsymmetricValueTransform = errors.New("value transform does not round-trip")
+
+// ErrLastMxRecord is returned when ProtectLastMx is set and a batch would
+// remove the last MX record at a name without AllowLastRecord.
+var ErrLastMxRecord = errors.New("refusing to remove the last MX record")
+
+// ErrInvalidZoneEdit is returned when a zone edit fails local validation.
+var ErrInvalidZoneEdit = errors.New("invalid zone edit")
+
+// ErrOutageWindowExceeded is returned for actions held through an outage
+// when CSC stays unreachable for longer than OutageWindow.
+var ErrOutageWindowExceeded = errors.New("CSC Domain Manager unreachable for longer than the outage window")
+
+// ErrCnameConflict is returned when a set of records places a CNAME at a key
+// alongside any other record, which DNS does not allow.
+var ErrCnameConflict = errors.New("CNAME record must be the only record at its key")
+
+// ErrSoaClaimed is returned when a zone's SOA is already managed by another
+// caller of ClaimSoa.
+var ErrSoaClaimed = errors.New("zone SOA is already managed")
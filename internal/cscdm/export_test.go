@@ -0,0 +1,24 @@
+package cscdm
+
+import "io"
+
+// Exported for tests in package cscdm_test.
+var JoinBatchErrors = joinBatchErrors
+
+// SetWarnOutput redirects batching warnings until the returned func is called.
+func SetWarnOutput(w io.Writer) func() {
+	previous := warnOutput
+	warnOutput = w
+
+	return func() { warnOutput = previous }
+}
+
+// Logf writes a line through the client's logger.
+func Logf(c *Client, format string, args ...any) {
+	c.logf(format, args...)
+}
+
+// FlushLoopDone returns the channel closed once the client's flush loop exits.
+func FlushLoopDone(c *Client) <-chan struct{} {
+	return c.flushLoopDone
+}
@@ -0,0 +1,31 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CheckHostingType returns an error wrapping ErrUnsupportedHostingType when
+// RecordHostingTypes is set and the zone's hosting type, matched
+// case-insensitively, is not among them. Zones are only checked when
+// RecordHostingTypes is set, since CSC may add hosting types at any time.
+func (c *Client) CheckHostingType(ctx context.Context, zoneName string) error {
+	if len(c.RecordHostingTypes) == 0 {
+		return nil
+	}
+
+	zone, err := c.getZone(ctx, zoneName)
+	if err != nil {
+		return err
+	}
+
+	for _, hostingType := range c.RecordHostingTypes {
+		if strings.EqualFold(zone.HostingType, hostingType) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: zone %s has hosting type %q, expected one of %s",
+		ErrUnsupportedHostingType, zoneName, zone.HostingType, strings.Join(c.RecordHostingTypes, ", "))
+}
@@ -0,0 +1,98 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+)
+
+// rejectLastMxPurges answers with ErrLastMxRecord every action in the batch
+// that helps remove the last MX record at a name, unless each such action
+// sets AllowLastRecord, and returns the actions left to submit. Removals are
+// counted against the whole batch, so several PURGEs that together empty a
+// name are caught even though none is the last on its own. Apex NS records
+// need no check here, since CheckApexNs refuses to remove any of them.
+func (c *Client) rejectLastMxPurges(batch []*RecordAction) []*RecordAction {
+	removals := make(map[string]map[string][]*RecordAction)
+	for _, action := range batch {
+		if action.RecordType != "MX" || action.Action == "ADD" {
+			continue
+		}
+		if action.Action == "EDIT" && action.CurrentKey == action.NewKey {
+			continue
+		}
+
+		if removals[action.ZoneName] == nil {
+			removals[action.ZoneName] = make(map[string][]*RecordAction)
+		}
+		name := zoneFqdn(action.ZoneName, action.CurrentKey)
+		removals[action.ZoneName][name] = append(removals[action.ZoneName][name], action)
+	}
+	if len(removals) == 0 {
+		return batch
+	}
+
+	rejected := make(map[*RecordAction]error)
+	for zoneName, names := range removals {
+		records, err := c.FetchZoneRecords(context.Background(), zoneName, "MX")
+		if err != nil {
+			for _, actions := range names {
+				for _, action := range actions {
+					rejected[action] = fmt.Errorf("unable to check for the last MX record at %s: %s", action.CurrentKey, err)
+				}
+			}
+			continue
+		}
+
+		remaining := make(map[string]int)
+		for _, record := range records {
+			remaining[zoneFqdn(zoneName, record.Key)]++
+		}
+		for _, action := range batch {
+			added := action.Action == "ADD" || (action.Action == "EDIT" && action.CurrentKey != action.NewKey)
+			if action.ZoneName == zoneName && action.RecordType == "MX" && added {
+				remaining[zoneFqdn(zoneName, action.NewKey)]++
+			}
+		}
+
+		for name, actions := range names {
+			remaining[name] -= len(actions)
+			if remaining[name] > 0 || allowLastRecord(actions) {
+				continue
+			}
+
+			for _, action := range actions {
+				rejected[action] = fmt.Errorf(
+					"%w at %s in zone %s: mail for the name would stop being delivered; set allow_last_mx_delete to remove it anyway",
+					ErrLastMxRecord, name, zoneName,
+				)
+			}
+		}
+	}
+
+	kept := make([]*RecordAction, 0, len(batch))
+	for _, action := range batch {
+		err, ok := rejected[action]
+		if !ok {
+			kept = append(kept, action)
+			continue
+		}
+
+		if rErr := c.returnError(action.ZoneName, action.RecordType, action.KeyId(), action.ValueId(), err); rErr != nil {
+			c.logf("failed to return error: %s", rErr.Error())
+		}
+	}
+
+	return kept
+}
+
+// allowLastRecord reports whether every action opts in to removing the last
+// record at its name.
+func allowLastRecord(actions []*RecordAction) bool {
+	for _, action := range actions {
+		if !action.AllowLastRecord {
+			return false
+		}
+	}
+
+	return true
+}
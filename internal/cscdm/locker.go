@@ -0,0 +1,63 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LOCK_POLL_INTERVAL is how often FileLocker retries a lock held by another
+// process.
+const LOCK_POLL_INTERVAL = 100 * time.Millisecond
+
+// Locker serializes zone edits across clients, including clients in other
+// processes, so they do not trip over each other's open zone edits. Lock
+// blocks until the zone is free or ctx ends, and returns a function that
+// releases it.
+type Locker interface {
+	Lock(ctx context.Context, zoneName string) (unlock func(), err error)
+}
+
+// FileLocker is a Locker backed by advisory locks on one file per zone in
+// Dir. It only serializes processes on the same host that use the same
+// directory; locks on network filesystems may not be honored. A lock is
+// released automatically if its holder exits.
+type FileLocker struct {
+	Dir string
+}
+
+func (l *FileLocker) Lock(ctx context.Context, zoneName string) (func(), error) {
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create lock directory: %s", err)
+	}
+
+	name := strings.NewReplacer("/", "_", `\`, "_").Replace(strings.ToLower(zoneName))
+	f, err := os.OpenFile(filepath.Join(l.Dir, name+".lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open lock file: %s", err)
+	}
+
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("unable to lock zone %s: %s", zoneName, err)
+		}
+		if locked {
+			break
+		}
+
+		if err := sleepContext(ctx, LOCK_POLL_INTERVAL); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("gave up waiting for lock on zone %s: %w", zoneName, err)
+		}
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
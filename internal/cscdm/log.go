@@ -0,0 +1,69 @@
+package cscdm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logFileMutex serializes writes to log files across every client, since
+// clients scoped by WithCredentials share the same path.
+var logFileMutex sync.Mutex
+
+// logf writes a line to stderr and, when LogFile is set, mirrors it to that
+// file. The client's credentials are redacted from the line first.
+func (c *Client) logf(format string, args ...any) {
+	line := strings.TrimSuffix(c.redact(fmt.Sprintf(format, args...)), "\n") + "\n"
+
+	fmt.Fprint(warnOutput, line)
+
+	if c.LogFile == "" {
+		return
+	}
+
+	err := c.appendLogFile(fmt.Sprintf("%s %s", time.Now().UTC().Format(time.RFC3339), line))
+	if err != nil {
+		fmt.Fprintf(warnOutput, "failed to write log file: %s\n", err.Error())
+	}
+}
+
+func (c *Client) redact(s string) string {
+	for _, secret := range []string{c.apiKey, c.apiToken} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "[REDACTED]")
+		}
+	}
+
+	return s
+}
+
+// appendLogFile appends a line to LogFile. A file that has grown past
+// MAX_LOG_FILE_SIZE is first moved aside to LogFile.1, replacing any older
+// one, so logs kept across many runs stay bounded.
+func (c *Client) appendLogFile(line string) error {
+	logFileMutex.Lock()
+	defer logFileMutex.Unlock()
+
+	if info, err := os.Stat(c.LogFile); err == nil && info.Size() >= MAX_LOG_FILE_SIZE {
+		if err := os.Rename(c.LogFile, c.LogFile+".1"); err != nil {
+			return fmt.Errorf("unable to rotate log file: %s", err)
+		}
+	}
+
+	f, err := os.OpenFile(c.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %s", err)
+	}
+
+	_, err = f.WriteString(line)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write log file: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PING_PATH is a cheap authenticated endpoint used to verify credentials.
+const PING_PATH = "zones?size=1"
+
+// Ping checks that CSC is reachable and accepts the client's credentials.
+// Failures to connect wrap ErrUnreachable and 401 or 403 responses wrap
+// ErrUnauthorized; any other unsuccessful status is returned as is. ctx
+// bounds the whole check.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", PING_PATH, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %s", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: status code %d", ErrUnauthorized, resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
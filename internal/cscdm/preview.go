@@ -0,0 +1,39 @@
+package cscdm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// editPreviewMutex serializes writes to edit preview files across every
+// client, since clients scoped by WithCredentials share the same path.
+var editPreviewMutex sync.Mutex
+
+// writeEditPreview appends a zone edit request to EditPreviewPath as a single
+// line of JSON.
+func (c *Client) writeEditPreview(payload ZoneEditReq) error {
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal zone edit preview: %s", err)
+	}
+
+	editPreviewMutex.Lock()
+	defer editPreviewMutex.Unlock()
+
+	f, err := os.OpenFile(c.EditPreviewPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open edit preview file: %s", err)
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write edit preview file: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package cscdm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HinfoValue is the structured form of an HINFO record value, written as
+// "<cpu> <os>" with either field optionally quoted.
+type HinfoValue struct {
+	Cpu string
+	Os  string
+}
+
+// ParseHinfoValue splits an HINFO record value into its fields.
+func ParseHinfoValue(value string) (*HinfoValue, error) {
+	fields, err := splitRdata(value)
+	if err != nil {
+		return nil, fmt.Errorf("HINFO value %q: %s", value, err)
+	}
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("HINFO value must be '<cpu> <os>', got %q", value)
+	}
+
+	return &HinfoValue{Cpu: fields[0], Os: fields[1]}, nil
+}
+
+// NaptrValue is the structured form of a NAPTR record value, written as
+// "<order> <preference> <flags> <service> <regexp> <replacement>".
+type NaptrValue struct {
+	Order       int64
+	Preference  int64
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// ParseNaptrValue splits a NAPTR record value into its fields.
+func ParseNaptrValue(value string) (*NaptrValue, error) {
+	fields, err := splitRdata(value)
+	if err != nil {
+		return nil, fmt.Errorf("NAPTR value %q: %s", value, err)
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("NAPTR value must be '<order> <preference> <flags> <service> <regexp> <replacement>', got %q", value)
+	}
+
+	var numbers [2]int64
+	for i, name := range []string{"order", "preference"} {
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil || n < 0 || n > 65535 {
+			return nil, fmt.Errorf("NAPTR %s must be a number between 0 and 65535, got %q", name, fields[i])
+		}
+		numbers[i] = n
+	}
+
+	return &NaptrValue{
+		Order:       numbers[0],
+		Preference:  numbers[1],
+		Flags:       fields[2],
+		Service:     fields[3],
+		Regexp:      fields[4],
+		Replacement: fields[5],
+	}, nil
+}
+
+// LocValue is the structured form of a LOC record value as defined in
+// RFC 1876. Coordinates are in decimal degrees, positive north and east;
+// distances are in meters.
+type LocValue struct {
+	Latitude            float64
+	Longitude           float64
+	Altitude            float64
+	Size                float64
+	HorizontalPrecision float64
+	VerticalPrecision   float64
+}
+
+// ParseLocValue parses a LOC record value of the form
+// "d1 [m1 [s1]] {N|S} d2 [m2 [s2]] {E|W} alt[m] [siz[m] [hp[m] [vp[m]]]]".
+func ParseLocValue(value string) (*LocValue, error) {
+	fields := strings.Fields(value)
+
+	latitude, rest, err := parseLocCoordinate(fields, "N", "S", 90)
+	if err != nil {
+		return nil, fmt.Errorf("LOC value %q: latitude %s", value, err)
+	}
+	longitude, rest, err := parseLocCoordinate(rest, "E", "W", 180)
+	if err != nil {
+		return nil, fmt.Errorf("LOC value %q: longitude %s", value, err)
+	}
+
+	if len(rest) < 1 || len(rest) > 4 {
+		return nil, fmt.Errorf("LOC value %q: expected an altitude followed by up to 3 precision fields", value)
+	}
+
+	// Size and precisions default as in RFC 1876 when omitted.
+	distances := []float64{0, 1, 10000, 10}
+	for i, field := range rest {
+		d, err := strconv.ParseFloat(strings.TrimSuffix(field, "m"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("LOC value %q: %q is not a distance in meters", value, field)
+		}
+		distances[i] = d
+	}
+
+	return &LocValue{
+		Latitude:            latitude,
+		Longitude:           longitude,
+		Altitude:            distances[0],
+		Size:                distances[1],
+		HorizontalPrecision: distances[2],
+		VerticalPrecision:   distances[3],
+	}, nil
+}
+
+// parseLocCoordinate reads degrees, optional minutes and seconds and a
+// hemisphere letter from the front of fields, returning the signed decimal
+// coordinate and the remaining fields.
+func parseLocCoordinate(fields []string, positive string, negative string, limit float64) (float64, []string, error) {
+	var parts []float64
+
+	for i, field := range fields {
+		hemisphere := strings.ToUpper(field)
+		if hemisphere == positive || hemisphere == negative {
+			if len(parts) == 0 {
+				return 0, nil, fmt.Errorf("is missing degrees")
+			}
+
+			coordinate := parts[0]
+			if len(parts) > 1 {
+				coordinate += parts[1] / 60
+			}
+			if len(parts) > 2 {
+				coordinate += parts[2] / 3600
+			}
+			if coordinate > limit {
+				return 0, nil, fmt.Errorf("must not exceed %g degrees", limit)
+			}
+			if hemisphere == negative {
+				coordinate = -coordinate
+			}
+
+			return coordinate, fields[i+1:], nil
+		}
+
+		if len(parts) == 3 {
+			return 0, nil, fmt.Errorf("must end with %s or %s", positive, negative)
+		}
+
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil || n < 0 {
+			return 0, nil, fmt.Errorf("has invalid component %q", field)
+		}
+		parts = append(parts, n)
+	}
+
+	return 0, nil, fmt.Errorf("must end with %s or %s", positive, negative)
+}
+
+// splitRdata splits a record value on whitespace, treating double-quoted
+// sections as single fields and honoring backslash escapes within them.
+func splitRdata(value string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted, escaped := false, false, false
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inField = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if quoted || escaped {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	return fields, nil
+}
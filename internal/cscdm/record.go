@@ -2,14 +2,31 @@ package cscdm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
+// zoneEditActionOrder is the order in which actions are submitted within a
+// single zone's batch.
+var zoneEditActionOrder = map[string]int{
+	"PURGE": 0,
+	"EDIT":  1,
+	"ADD":   2,
+}
+
 type ZoneEditReq struct {
 	ZoneName string     `json:"zoneName"`
 	Edits    []ZoneEdit `json:"edits"`
@@ -26,6 +43,62 @@ type ZoneEdit struct {
 	NewValue        string `json:"newValue,omitempty"`
 	NewTtl          int64  `json:"newTtl,omitempty"`
 	NewPriority     int64  `json:"newPriority,omitempty"`
+	// NewWeight and NewPort are an SRV record's weight and target port.
+	NewWeight int64 `json:"newWeight,omitempty"`
+	NewPort   int32 `json:"newPort,omitempty"`
+	// NewSoa holds the fields an EDIT of a zone's SOA record changes. It is
+	// only set on edits with a RecordType of "SOA".
+	NewSoa *ZoneSoaEdit `json:"newSoa,omitempty"`
+	// NewMetadata is free-form labels for the record, such as an owner or
+	// cost center. CSC ignores it for record types it keeps no metadata on.
+	// A nil map leaves a record's labels as they are; an empty one is sent
+	// as such and clears them.
+	NewMetadata map[string]string `json:"newMetadata,omitempty"`
+	// ZeroTtl sends a NewTtl of zero as an explicit TTL of zero, which is
+	// otherwise left out so CSC applies the zone default. It is ignored
+	// when NewTtl is set.
+	ZeroTtl bool `json:"-"`
+	// SkipRefetch builds the returned record from the edit itself once the
+	// edit completes, reading only records of its type to learn the id,
+	// instead of re-reading the whole zone. Server-side normalization of the
+	// submitted values is not reflected. It is never sent to CSC.
+	SkipRefetch bool `json:"-"`
+	// AllowLastRecord lets the action remove the last MX record at its name
+	// when the client's ProtectLastMx is set. It is never sent to CSC.
+	AllowLastRecord bool `json:"-"`
+}
+
+// MarshalJSON encodes the edit, including a zero newTtl when ZeroTtl is set
+// and an empty newMetadata when NewMetadata is empty but not nil. CAA values
+// are sent in the form CaaValue.String gives, with flags of 0 filled in when
+// they were left out.
+func (ze ZoneEdit) MarshalJSON() ([]byte, error) {
+	type plainZoneEdit ZoneEdit
+	if ze.RecordType == "CAA" {
+		ze.CurrentValue = encodeCaaValue(ze.CurrentValue)
+		ze.NewValue = encodeCaaValue(ze.NewValue)
+	}
+	zeroTtl := ze.ZeroTtl && ze.NewTtl == 0
+	clearMetadata := ze.NewMetadata != nil && len(ze.NewMetadata) == 0
+	if !zeroTtl && !clearMetadata {
+		return json.Marshal(plainZoneEdit(ze))
+	}
+
+	// The outer fields hide the plain ones of the same name, so both are
+	// always filled in from the edit.
+	edit := struct {
+		plainZoneEdit
+		NewTtl      *int64             `json:"newTtl,omitempty"`
+		NewMetadata *map[string]string `json:"newMetadata,omitempty"`
+	}{plainZoneEdit: plainZoneEdit(ze)}
+	if ze.NewTtl != 0 || zeroTtl {
+		edit.NewTtl = &ze.NewTtl
+	}
+	if ze.NewMetadata != nil {
+		edit.NewMetadata = &ze.NewMetadata
+	}
+
+	return json.Marshal(edit)
 }
 
 func (ze *ZoneEdit) KeyId() string {
@@ -61,24 +134,61 @@ type ZoneEditErr struct {
 	Value       string `json:"value"`
 }
 
+func (e *ZoneEditErr) Error() string {
+	if explanation := ExplainErrorCode(e.Code); explanation != "" {
+		return fmt.Sprintf("%s: %s: %q (%s)", e.Code, e.Description, e.Value, explanation)
+	}
+
+	return fmt.Sprintf("%s: %s: %q", e.Code, e.Description, e.Value)
+}
+
+// errorCodeExplanations maps CSC error codes to guidance on what caused them
+// and what to do about them. Only codes the client is known to receive are
+// listed.
+var errorCodeExplanations = map[string]string{
+	"OPEN_ZONE_EDITS": "the zone has edits still being applied, usually from another run or the CSC portal; wait for them to finish and try again",
+}
+
+// ExplainErrorCode returns guidance for a CSC error code, or an empty string
+// when the code is not known.
+func ExplainErrorCode(code string) string {
+	return errorCodeExplanations[code]
+}
+
 type ZoneEditStatus struct {
 	Content struct {
 		Status string `json:"status"`
 	} `json:"content"`
 }
 
+type Zones struct {
+	Meta struct {
+		NumResults int64 `json:"numResults"`
+		Pages      int64 `json:"pages"`
+	} `json:"meta"`
+	Zones []Zone `json:"zones"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"links"`
+}
+
 type Zone struct {
-	ZoneName    string          `json:"zoneName"`
-	HostingType string          `json:"hostingType"`
-	A           []ZoneRecord    `json:"a"`
-	CNAME       []ZoneRecord    `json:"cname"`
-	AAAA        []ZoneRecord    `json:"aaaa"`
-	TXT         []ZoneRecord    `json:"txt"`
-	MX          []ZoneRecord    `json:"mx"`
-	NS          []ZoneRecord    `json:"ns"`
-	SRV         []ZoneSrvRecord `json:"srv"`
-	CAA         []ZoneRecord    `json:"caa"`
-	SOA         ZoneSoaRecord   `json:"soa"`
+	ZoneName    string        `json:"zoneName"`
+	HostingType string        `json:"hostingType"`
+	Status      string        `json:"status"`
+	A           []ZoneRecord  `json:"a"`
+	CNAME       []ZoneRecord  `json:"cname"`
+	AAAA        []ZoneRecord  `json:"aaaa"`
+	TXT         []ZoneRecord  `json:"txt"`
+	MX          []ZoneRecord  `json:"mx"`
+	NS          []ZoneRecord  `json:"ns"`
+	SRV         []ZoneRecord  `json:"srv"`
+	CAA         []ZoneRecord  `json:"caa"`
+	TLSA        []ZoneRecord  `json:"tlsa"`
+	HINFO       []ZoneRecord  `json:"hinfo"`
+	LOC         []ZoneRecord  `json:"loc"`
+	NAPTR       []ZoneRecord  `json:"naptr"`
+	SOA         ZoneSoaRecord `json:"soa"`
 }
 
 type ZoneRecord struct {
@@ -88,11 +198,15 @@ type ZoneRecord struct {
 	Ttl      int64  `json:"ttl,omitempty"`
 	Priority int64  `json:"priority"`
 	Status   string `json:"status"`
-}
-
-type ZoneSrvRecord struct {
-	ZoneRecord
-	Port int32 `json:"port"`
+	// Weight and Port are set on SRV records only.
+	Weight int64 `json:"weight,omitempty"`
+	Port   int32 `json:"port,omitempty"`
+	// PropagationStatus is CSC's view of whether the record has reached
+	// the zone's nameservers, such as "PENDING" or "SYNCED". It is empty
+	// when CSC does not report one.
+	PropagationStatus string `json:"propagationStatus,omitempty"`
+	// Metadata is the record's free-form labels, nil when CSC reports none.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type ZoneSoaRecord struct {
@@ -107,12 +221,60 @@ type ZoneSoaRecord struct {
 	MasterHost string `json:"masterHost"`
 }
 
-func (c *Client) PerformRecordAction(payload *RecordAction) (*ZoneRecord, error) {
-	returnChan := make(chan *ZoneRecord, 1)
-	errorChan := make(chan error, 1)
-	c.enqueue(payload, returnChan, errorChan)
+// PerformRecordAction queues an action and waits for its result. If ctx ends
+// first the call returns its error; an action not yet submitted is dropped,
+// while one already sent to CSC may still be applied. A batch stops polling
+// CSC once every caller waiting on its zone has given up.
+func (c *Client) PerformRecordAction(ctx context.Context, payload *RecordAction) (*ZoneRecord, error) {
+	queued, err := c.submitRecordActions(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.awaitRecordAction(ctx, payload, queued[0].returnChan, queued[0].errorChan)
+}
 
+// submitRecordActions checks every action and then enqueues them together,
+// so they are submitted to CSC in the same zone edit. Nothing is enqueued
+// if any check fails.
+func (c *Client) submitRecordActions(ctx context.Context, payloads ...*RecordAction) ([]queuedAction, error) {
+	queued := make([]queuedAction, len(payloads))
+	for i, payload := range payloads {
+		if payload.Action == "ADD" || payload.Action == "PURGE" {
+			if err := CheckApexNs(payload.ZoneName, payload.RecordType, payload.KeyId()); err != nil {
+				return nil, err
+			}
+		}
+		if payload.Action == "ADD" || payload.Action == "EDIT" {
+			if err := c.CheckValueTransform(payload.RecordType, payload.NewValue); err != nil {
+				return nil, err
+			}
+		}
+
+		payload.ctx = ctx
+		queued[i] = queuedAction{action: payload, returnChan: make(chan *ZoneRecord, 1), errorChan: make(chan error, 1)}
+	}
+
+	c.enqueue(queued...)
+
+	if c.Synchronous {
+		err := c.flush()
+
+		if err != nil {
+			c.logf("failed to flush queue: %s", err.Error())
+		}
+	}
+
+	return queued, nil
+}
+
+// awaitRecordAction waits for the result of an action queued by
+// submitRecordActions. The channels are buffered, so a batch answering a
+// caller that gave up never blocks.
+func (c *Client) awaitRecordAction(ctx context.Context, payload *RecordAction, returnChan chan *ZoneRecord, errorChan chan error) (*ZoneRecord, error) {
 	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("gave up waiting for %s %s in %s, the edit may still be applied: %w", payload.RecordType, payload.KeyId(), payload.ZoneName, ctx.Err())
 	case zoneRecord, ok := <-returnChan:
 		if !ok {
 			return nil, fmt.Errorf("return channel closed for %s %s in %s: CHECK TF WARN LOGS", payload.RecordType, payload.KeyId(), payload.ZoneName)
@@ -126,13 +288,109 @@ func (c *Client) PerformRecordAction(payload *RecordAction) (*ZoneRecord, error)
 	}
 }
 
+// PerformRecordEdit performs an EDIT, handling key changes according to the
+// client's RenameStrategy.
+func (c *Client) PerformRecordEdit(ctx context.Context, payload *RecordAction) (*ZoneRecord, error) {
+	if payload.Action != "EDIT" || payload.CurrentKey == payload.NewKey || c.RenameStrategy == RENAME_STRATEGY_EDIT {
+		return c.PerformRecordAction(ctx, payload)
+	}
+
+	if c.RenameStrategy == RENAME_STRATEGY_ERROR {
+		return nil, fmt.Errorf(
+			"cannot rename %s record in zone %s from '%s' to '%s': rename_strategy is %q, "+
+				"replace the resource instead or set rename_strategy to %q or %q",
+			payload.RecordType, payload.ZoneName, payload.CurrentKey, payload.NewKey, RENAME_STRATEGY_ERROR,
+			RENAME_STRATEGY_EDIT, RENAME_STRATEGY_REPLACE,
+		)
+	}
+
+	purge := &RecordAction{
+		ZoneEdit: ZoneEdit{
+			Action:          "PURGE",
+			RecordType:      payload.RecordType,
+			CurrentKey:      payload.CurrentKey,
+			CurrentValue:    payload.CurrentValue,
+			AllowLastRecord: payload.AllowLastRecord,
+		},
+		ZoneName: payload.ZoneName,
+	}
+	add := &RecordAction{
+		ZoneEdit: ZoneEdit{
+			Action:      "ADD",
+			RecordType:  payload.RecordType,
+			NewKey:      payload.NewKey,
+			NewValue:    payload.NewValue,
+			NewTtl:      payload.NewTtl,
+			NewPriority: payload.NewPriority,
+			NewWeight:   payload.NewWeight,
+			NewPort:     payload.NewPort,
+			NewMetadata: payload.NewMetadata,
+			ZeroTtl:     payload.ZeroTtl,
+			SkipRefetch: payload.SkipRefetch,
+		},
+		ZoneName: payload.ZoneName,
+	}
+
+	// Both halves are enqueued together so they go to CSC in one zone edit,
+	// with the PURGE ordered ahead of the ADD. Should CSC reject the ADD, the
+	// whole edit fails and the original record is kept.
+	queued, err := c.submitRecordActions(ctx, purge, add)
+	if err != nil {
+		return nil, err
+	}
+
+	_, purgeErr := c.awaitRecordAction(ctx, purge, queued[0].returnChan, queued[0].errorChan)
+	record, addErr := c.awaitRecordAction(ctx, add, queued[1].returnChan, queued[1].errorChan)
+
+	if purgeErr != nil {
+		return nil, fmt.Errorf("failed to remove '%s' while renaming it to '%s': %s", payload.CurrentKey, payload.NewKey, purgeErr)
+	}
+	if addErr != nil {
+		return nil, fmt.Errorf("failed to add '%s' while renaming it from '%s': %s", payload.NewKey, payload.CurrentKey, addErr)
+	}
+
+	return record, nil
+}
+
 func (c *Client) editZones() error {
+	var checkedZones map[string]*Zone
+	if c.DependencyChecks {
+		checkedZones = c.queuedZones()
+	}
+
 	c.batchMutex.Lock()
-	defer c.clear()
-	defer c.batchMutex.Unlock()
+
+	// Idle timer flushes with nothing queued are common; skip them entirely.
+	if len(c.recordActionQueue) == 0 {
+		c.batchMutex.Unlock()
+		return nil
+	}
+
+	// Take the queue so actions enqueued while this batch is waiting on CSC,
+	// possible under AsyncWait, form the next batch.
+	batch := c.dropAbandoned(c.recordActionQueue)
+	c.recordActionQueue = nil
+	if len(batch) == 0 {
+		c.batchMutex.Unlock()
+		return nil
+	}
+
+	if c.ProtectLastMx {
+		batch = c.rejectLastMxPurges(batch)
+	}
+
+	// Batches re-queued after a zone lock conflict keep their callers'
+	// channels open for a later flush.
+	var requeued []*RecordAction
+	var requeuedMutex sync.Mutex
+	// outageHeld counts the requeued actions held because CSC was
+	// unreachable, bounded by OutageBufferSize.
+	var outageHeld int
 
 	zoneEdits := make(map[string][]ZoneEdit)
-	for _, recordAction := range c.recordActionQueue {
+	zoneActions := make(map[string][]*RecordAction)
+	for _, recordAction := range batch {
+		zoneActions[recordAction.ZoneName] = append(zoneActions[recordAction.ZoneName], recordAction)
 		zoneEdits[recordAction.ZoneName] = append(
 			zoneEdits[recordAction.ZoneName],
 			ZoneEdit{
@@ -146,13 +404,65 @@ func (c *Client) editZones() error {
 				NewValue:        recordAction.NewValue,
 				NewTtl:          recordAction.NewTtl,
 				NewPriority:     recordAction.NewPriority,
+				NewWeight:       recordAction.NewWeight,
+				NewPort:         recordAction.NewPort,
+				NewMetadata:     recordAction.NewMetadata,
+				ZeroTtl:         recordAction.ZeroTtl,
+				SkipRefetch:     recordAction.SkipRefetch,
 			},
 		)
 	}
 
+	// Apply each zone's edits in a fixed order regardless of enqueue timing:
+	// removals first so a replacement record (e.g. a CNAME swapped for an A
+	// record at the same key) never collides with the one it replaces.
+	for _, edits := range zoneEdits {
+		sort.SliceStable(edits, func(i, j int) bool {
+			return zoneEditActionOrder[edits[i].Action] < zoneEditActionOrder[edits[j].Action]
+		})
+	}
+
+	if c.DependencyChecks {
+		for zone, edits := range zoneEdits {
+			// A zone enqueued after queuedZones read the others goes
+			// unchecked.
+			c.recordOrphans(zone, checkedZones[zone], edits)
+		}
+	}
+
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(zoneEdits))
 
+	// submitted is done once every zone's edits have been sent to CSC, or
+	// have failed before getting that far.
+	var submitted sync.WaitGroup
+
+	// Under FailFast the first zone to fail cancels the work of the others.
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	// zoneErrs holds the error each failed zone's callers were answered
+	// with, so the flush fails too and the flush loop backs off.
+	var zoneErrs []error
+	var zoneErrsMutex sync.Mutex
+
+	// failZone answers every caller of the zone's actions in this batch
+	// with err. Callers queued for the zone since, possible under
+	// AsyncWait, are left to their own batch.
+	failZone := func(zone string, err error) {
+		if c.FailFast {
+			cancel(err)
+		}
+
+		zoneErrsMutex.Lock()
+		zoneErrs = append(zoneErrs, err)
+		zoneErrsMutex.Unlock()
+
+		rErr := c.returnErrorToActions(zoneActions[zone], err)
+		if rErr != nil {
+			errChan <- fmt.Errorf("failed to return error: %s", rErr)
+		}
+	}
+
 	for zone, edits := range zoneEdits {
 		payload := ZoneEditReq{
 			ZoneName: zone,
@@ -160,38 +470,124 @@ func (c *Client) editZones() error {
 		}
 
 		wg.Add(1)
+		submitted.Add(1)
 		go func(payload ZoneEditReq) {
 			defer wg.Done()
 
-			editId, err := c.editZone(payload)
-			if err != nil {
-				err = fmt.Errorf("failed to edit zone %s: %s", payload.ZoneName, err)
-				rErr := c.returnErrorToZone(payload.ZoneName, err)
+			markSubmitted := sync.OnceFunc(submitted.Done)
+			defer markSubmitted()
+
+			if ctx.Err() != nil {
+				failZone(payload.ZoneName, fmt.Errorf("skipped zone %s edits after an earlier failure: %s", payload.ZoneName, context.Cause(ctx)))
+				return
+			}
 
-				if rErr != nil {
-					errChan <- fmt.Errorf("failed to return error: %s", rErr)
+			// The zone's edits are abandoned once every caller waiting on
+			// them has given up. An earlier failure under FailFast only stops
+			// them before they are submitted; once CSC has them, they are
+			// waited on like any other.
+			zoneCtx, stopZone := callersContext(context.Background(), zoneActions[payload.ZoneName])
+			defer stopZone()
+
+			if c.ZoneLocker != nil {
+				lockCtx, stopLock := context.WithCancelCause(zoneCtx)
+				stopAfter := context.AfterFunc(ctx, func() { stopLock(context.Cause(ctx)) })
+				unlock, err := c.ZoneLocker.Lock(lockCtx, payload.ZoneName)
+				stopAfter()
+				stopLock(nil)
+				if err != nil {
+					failZone(payload.ZoneName, fmt.Errorf("failed to lock zone %s: %s", payload.ZoneName, err))
+					return
 				}
+				defer unlock()
+			}
+
+			if ctx.Err() != nil {
+				failZone(payload.ZoneName, fmt.Errorf("skipped zone %s edits after an earlier failure: %s", payload.ZoneName, context.Cause(ctx)))
 				return
 			}
 
-			err = c.waitForZoneEdits(*editId)
+			// Records the edit adds that were already present are never
+			// adopted should the response be lost.
+			before := c.adoptionSnapshot(payload)
+
+			wire := c.encodeZoneEdits(payload)
+			if c.EditPreviewPath != "" {
+				if err := c.writeEditPreview(wire); err != nil {
+					c.logf("failed to record edit preview: %s", err.Error())
+				}
+			}
+
+			editId, err := c.editZone(zoneCtx, wire)
+			markSubmitted()
 			if err != nil {
-				err = fmt.Errorf("failed to wait for %s zone edits: %s", payload.ZoneName, err)
-				rErr := c.returnErrorToZone(payload.ZoneName, err)
+				var zeErr *ZoneEditErr
+				if errors.As(err, &zeErr) && zeErr.Code == "OPEN_ZONE_EDITS" && c.canRequeue(zoneActions[payload.ZoneName]) {
+					actions := zoneActions[payload.ZoneName]
+					for _, action := range actions {
+						action.requeues++
+					}
+					c.logf("[WARN] zone %s is locked by open edits, re-queueing its batch (%d of %d)", payload.ZoneName, actions[0].requeues, c.ZoneLockRequeues)
+
+					requeuedMutex.Lock()
+					requeued = append(requeued, actions...)
+					requeuedMutex.Unlock()
+					return
+				} else if errors.Is(err, ErrUnreachable) && c.OutageWindow > 0 && !c.Synchronous {
+					actions := zoneActions[payload.ZoneName]
+
+					requeuedMutex.Lock()
+					hErr := c.holdThroughOutage(actions, outageHeld, err)
+					if hErr == nil {
+						outageHeld += len(actions)
+						requeued = append(requeued, actions...)
+					}
+					requeuedMutex.Unlock()
+
+					if hErr == nil {
+						c.logf("[WARN] CSC is unreachable, holding zone %s edits for a later flush: %s", payload.ZoneName, err)
+						return
+					}
+					failZone(payload.ZoneName, fmt.Errorf("failed to edit zone %s: %w", payload.ZoneName, hErr))
+					return
+				} else if errors.Is(err, ErrOutcomeUnknown) {
+					// The edit may have been applied even though the response
+					// was lost. Once it is seen to have been, adopt the records
+					// it added rather than leaving the caller to create them a
+					// second time.
+					if zone := c.awaitLostEdit(zoneCtx, payload, before); zone != nil {
+						rErr := c.returnAdoptedRecords(zone, payload, before)
 
-				if rErr != nil {
-					errChan <- fmt.Errorf("failed to return error: %s", rErr)
+						if rErr != nil {
+							errChan <- fmt.Errorf("failed to return error: %s", rErr)
+						}
+					}
 				}
+
+				failZone(payload.ZoneName, fmt.Errorf("failed to edit zone %s: %w", payload.ZoneName, err))
+				return
+			}
+
+			err = c.waitForZoneEdits(zoneCtx, *editId)
+			if err != nil {
+				failZone(payload.ZoneName, fmt.Errorf("failed to wait for %s zone edits: %s", payload.ZoneName, err))
 				return
 			}
 
 			c.invalidateZoneCache(payload.ZoneName)
 
-			recordsByType := make(map[string][]string)
+			editsByType := make(map[string][]ZoneEdit)
 
 			for _, edit := range payload.Edits {
-				if edit.Action == "PURGE" {
-					err := c.returnRecord(payload.ZoneName, edit.RecordType, edit.KeyId(), edit.ValueId(), nil)
+				if edit.Action == "PURGE" || edit.SkipRefetch {
+					var record *ZoneRecord
+					var err error
+					if edit.Action != "PURGE" {
+						record, err = c.synthesizeRecord(payload.ZoneName, edit)
+					}
+					if err == nil {
+						err = c.returnRecord(payload.ZoneName, edit.RecordType, edit.KeyId(), edit.ValueId(), record)
+					}
 					if err != nil {
 						rErr := c.returnError(payload.ZoneName, edit.RecordType, edit.KeyId(), edit.ValueId(), err)
 
@@ -201,26 +597,28 @@ func (c *Client) editZones() error {
 						return
 					}
 				} else {
-					recordsByType[edit.RecordType] = append(recordsByType[edit.RecordType], edit.KeyId())
+					editsByType[edit.RecordType] = append(editsByType[edit.RecordType], edit)
 				}
 			}
 
-			if len(recordsByType) > 0 {
+			if len(editsByType) > 0 {
 				zone, err := c.GetZone(payload.ZoneName)
 				if err != nil {
-					rErr := c.returnErrorToZone(payload.ZoneName, err)
-
-					if rErr != nil {
-						errChan <- fmt.Errorf("failed to return error: %s", rErr)
-					}
+					failZone(payload.ZoneName, err)
 					return
 				}
 
-				for recordType, keys := range recordsByType {
+				for recordType, edits := range editsByType {
 					records := c.GetRecordsByType(zone, recordType)
-					if records == nil {
+					if !slices.Contains(SupportedRecordTypes(), recordType) {
 						err := fmt.Errorf("unsupported record type: %s", recordType)
-						rErr := c.returnErrorToZoneWithRecordType(payload.ZoneName, recordType, err)
+						var typeActions []*RecordAction
+						for _, action := range zoneActions[payload.ZoneName] {
+							if action.RecordType == recordType {
+								typeActions = append(typeActions, action)
+							}
+						}
+						rErr := c.returnErrorToActions(typeActions, err)
 
 						if rErr != nil {
 							errChan <- fmt.Errorf("failed to return error: %s", rErr)
@@ -228,10 +626,24 @@ func (c *Client) editZones() error {
 						return
 					}
 
-					for key, record := range c.GetRecordsByKeys(records, keys) {
-						err := c.returnRecord(payload.ZoneName, recordType, key, record.Value, record)
+					// Match on value too so each record of a multi-value set
+					// answers its own caller, even when CSC rewrote the value
+					// into an equivalent form, falling back to the first
+					// record with the key.
+					for _, edit := range edits {
+						key, value := edit.KeyId(), edit.ValueId()
+						record := c.findRecord(records, recordType, key, value)
+						if record == nil {
+							record = c.GetRecordByKey(records, key)
+							if record == nil {
+								continue
+							}
+							key, value = record.Key, record.Value
+						}
+
+						err := c.returnRecord(payload.ZoneName, recordType, key, value, record)
 						if err != nil {
-							rErr := c.returnError(payload.ZoneName, recordType, key, record.Value, err)
+							rErr := c.returnError(payload.ZoneName, recordType, key, value, err)
 
 							if rErr != nil {
 								errChan <- fmt.Errorf("failed to return error: %s", rErr)
@@ -244,97 +656,327 @@ func (c *Client) editZones() error {
 		}(payload)
 	}
 
-	wg.Wait()
-	close(errChan)
+	// complete waits for every zone to finish and answers any caller left
+	// over, returning the batch's errors.
+	complete := func() error {
+		wg.Wait()
+		close(errChan)
 
-	if len(errChan) > 0 {
-		var errStrs []string
+		var errs []error
 		for err := range errChan {
-			errStrs = append(errStrs, err.Error())
+			errs = append(errs, err)
+		}
+		errs = append(errs, zoneErrs...)
+		if ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("batch stopped early: %s", context.Cause(ctx)))
+		}
+		cancel(nil)
+
+		c.clear(batch, requeued)
+
+		return joinBatchErrors(errs)
+	}
+
+	if !c.AsyncWait {
+		wg.Wait()
+		c.batchMutex.Unlock()
+
+		return complete()
+	}
+
+	// Under AsyncWait the batch lets go of the queue once its edits are
+	// submitted, so the next batch can be submitted while CSC applies this
+	// one. Its callers are answered as each zone completes.
+	submitted.Wait()
+	c.asyncWaits.Add(1)
+	c.batchMutex.Unlock()
+
+	go func() {
+		defer c.asyncWaits.Done()
+
+		if err := complete(); err != nil {
+			c.logf("failed to complete zone edits: %s", err.Error())
+		}
+	}()
+
+	return nil
+}
+
+// canRequeue reports whether a zone's batch may be re-queued for a later
+// flush after a zone lock conflict, rather than failing its callers.
+func (c *Client) canRequeue(actions []*RecordAction) bool {
+	if c.Synchronous || len(actions) == 0 {
+		return false
+	}
+
+	for _, action := range actions {
+		if action.requeues >= c.ZoneLockRequeues {
+			return false
+		}
+	}
+
+	return true
+}
+
+// holdThroughOutage decides whether a zone's actions, which failed to reach
+// CSC with cause, may be held for a later flush under OutageWindow, given
+// that held actions are already being held by this batch. It marks actions
+// held for the first time, returning an error for the callers instead when
+// the window has passed or the buffer is full.
+func (c *Client) holdThroughOutage(actions []*RecordAction, held int, cause error) error {
+	now := time.Now()
+	for _, action := range actions {
+		if !action.unreachableSince.IsZero() && now.Sub(action.unreachableSince) >= c.OutageWindow {
+			return fmt.Errorf("%w of %s: %w", ErrOutageWindowExceeded, c.OutageWindow, cause)
 		}
+	}
+
+	if held+len(actions) > c.OutageBufferSize {
+		return fmt.Errorf("outage buffer of %d actions is full: %w", c.OutageBufferSize, cause)
+	}
 
-		return fmt.Errorf("%d error(s) in batch zone edits: %s", len(errStrs), strings.Join(errStrs, ", "))
+	for _, action := range actions {
+		if action.unreachableSince.IsZero() {
+			action.unreachableSince = now
+		}
 	}
 
 	return nil
 }
 
-func (c *Client) editZone(payload ZoneEditReq) (*string, error) {
+// synthesizeRecord builds the record an ADD or EDIT produced from the edit
+// itself, reading only the zone's records of that type for the id CSC
+// assigned.
+func (c *Client) synthesizeRecord(zoneName string, edit ZoneEdit) (*ZoneRecord, error) {
+	records, err := c.FetchZoneRecords(context.Background(), zoneName, edit.RecordType)
+	if err != nil {
+		return nil, err
+	}
+
+	found := c.findRecord(records, edit.RecordType, edit.NewKey, edit.NewValue)
+	if found == nil {
+		return nil, fmt.Errorf("%w: record of type %s with key '%s' and value '%s' was not found in zone %s after edit", ErrRecordNotFound, edit.RecordType, edit.NewKey, edit.NewValue, zoneName)
+	}
+
+	return &ZoneRecord{
+		Id:       found.Id,
+		Key:      edit.NewKey,
+		Value:    edit.NewValue,
+		Ttl:      edit.NewTtl,
+		Priority: edit.NewPriority,
+		Weight:   edit.NewWeight,
+		Port:     edit.NewPort,
+		Metadata: edit.NewMetadata,
+		Status:   found.Status,
+	}, nil
+}
+
+// joinBatchErrors combines the errors collected from a batch's zones into
+// one. The messages are sorted so the same set of failures always produces
+// the same error, whatever order the zones finished in.
+func joinBatchErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	errStrs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		errStrs = append(errStrs, err.Error())
+	}
+	sort.Strings(errStrs)
+
+	return fmt.Errorf("%d error(s) in batch zone edits: %s", len(errStrs), strings.Join(errStrs, ", "))
+}
+
+func (c *Client) editZone(ctx context.Context, payload ZoneEditReq) (*string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal record payload: %s", err)
 	}
 
-	for {
-		createResp, err := c.http.Post("zones/edits", "application/json", bytes.NewBuffer(body))
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.EditPath, bytes.NewBuffer(body))
 		if err != nil {
-			return nil, fmt.Errorf("failed to send request: %s", err)
+			return nil, fmt.Errorf("unable to create request: %s", err)
 		}
-		defer createResp.Body.Close()
+		req.Header.Set("Content-Type", "application/json")
+		c.tagChange(req)
 
-		if createResp.StatusCode != 201 {
-			var createErrJson ZoneEditErr
-			err = json.NewDecoder(createResp.Body).Decode(&createErrJson)
-			if err != nil {
-				return nil, fmt.Errorf("unable to unmarshal create record error response: %s", err)
+		createResp, err := c.http.Do(req)
+		if err != nil {
+			// A connection that was never made cannot have applied anything.
+			// Any other failure, such as a reset once connected, may have
+			// come after CSC accepted the edit.
+			var opErr *net.OpError
+			if errors.As(err, &opErr) && opErr.Op == "dial" {
+				return nil, fmt.Errorf("failed to send request: %w: %s", ErrUnreachable, err)
 			}
+			return nil, fmt.Errorf("failed to send request: %w: %s", ErrOutcomeUnknown, err)
+		}
+		respBody, err := io.ReadAll(createResp.Body)
+		createResp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w: %s", ErrOutcomeUnknown, err)
+		}
+
+		// CSC may report an error in the body without an error status code,
+		// so an error code is checked for whatever the status.
+		var createErrJson ZoneEditErr
+		decodeErr := json.Unmarshal(respBody, &createErrJson)
+		if decodeErr == nil && createErrJson.Code != "" {
+			err = fmt.Errorf("request returned error with status code %d: %w", createResp.StatusCode, &createErrJson)
+		} else if createResp.StatusCode >= 500 {
+			// A gateway may time out after CSC has accepted the edit.
+			err = fmt.Errorf("request returned unsuccessful status code %d: %w", createResp.StatusCode, ErrOutcomeUnknown)
+		} else if createResp.StatusCode != 200 && createResp.StatusCode != 201 {
+			err = fmt.Errorf("request returned unsuccessful status code %d", createResp.StatusCode)
+		}
 
-			if createErrJson.Code == "OPEN_ZONE_EDITS" {
-				time.Sleep(POLL_INTERVAL)
+		if err != nil {
+			if retry, delay := c.shouldRetry(attempt, createResp.StatusCode, err); retry {
+				if sErr := sleepContext(ctx, delay); sErr != nil {
+					return nil, fmt.Errorf("gave up retrying: %s: %s", context.Cause(ctx), err)
+				}
 				continue
 			}
 
-			return nil, fmt.Errorf("request returned unsuccessful status code: %s", err)
+			if attempt > 0 {
+				lastError := fmt.Sprintf("status code %d", createResp.StatusCode)
+				if decodeErr == nil && createErrJson.Code != "" {
+					lastError = createErrJson.Code
+				}
+				return nil, fmt.Errorf("gave up after %d attempts over %s, last error %s: %w", attempt+1, time.Since(start).Round(time.Millisecond), lastError, err)
+			}
+			return nil, err
 		}
 
 		var createJson ZoneEditRes
-		err = json.NewDecoder(createResp.Body).Decode(&createJson)
+		err = json.Unmarshal(respBody, &createJson)
 		if err != nil {
 			return nil, fmt.Errorf("unable to unmarshal create record response: %s", err)
 		}
+		if createJson.Links.Status == "" {
+			return nil, fmt.Errorf("create record response is missing the edit status link")
+		}
 
 		editStatusLink := strings.Split(createJson.Links.Status, "/")
 		return &editStatusLink[len(editStatusLink)-1], nil
 	}
 }
 
-func (c *Client) waitForZoneEdits(editId string) error {
-	for {
-		editStatusResp, err := c.http.Get(fmt.Sprintf("zones/edits/status/%s", editId))
+func (c *Client) waitForZoneEdits(ctx context.Context, editId string) error {
+	for attempt, retries := 0, 0; ; attempt++ {
+		editStatusJson, statusCode, err := c.fetchZoneEditStatus(ctx, editId)
 		if err != nil {
-			return fmt.Errorf("failed to send request: %s", err)
+			if ctx.Err() != nil {
+				return fmt.Errorf("stopped waiting, edits may still be applied: %s", context.Cause(ctx))
+			}
+			// The transport has already retried server errors.
+			if statusCode < 500 {
+				if retry, delay := c.shouldRetry(retries, statusCode, err); retry {
+					retries++
+					if sleepContext(ctx, delay) != nil {
+						return fmt.Errorf("stopped waiting, edits may still be applied: %s", context.Cause(ctx))
+					}
+					continue
+				}
+			}
+			return err
 		}
-		defer editStatusResp.Body.Close()
 
-		var editStatusJson ZoneEditStatus
-		err = json.NewDecoder(editStatusResp.Body).Decode(&editStatusJson)
-		if err != nil {
-			return fmt.Errorf("unable to unmarshal edit status response: %s", err)
+		status := editStatusJson.Content.Status
+		if c.StatusCallback != nil {
+			c.StatusCallback(editId, status)
 		}
 
-		if editStatusJson.Content.Status == "COMPLETED" {
+		switch zoneEditStatusClasses[status] {
+		case editStatusCompleted:
 			return nil
-		}
-
-		if editStatusJson.Content.Status == "FAILED" {
+		case editStatusFailed:
 			err = c.cancelZoneEdit(editId)
 			if err != nil {
-				return fmt.Errorf("zone edits returned status FAILED: failed to cancel zone edits: %s", err)
+				return fmt.Errorf("zone edits returned status %s: failed to cancel zone edits: %s", status, err)
 			}
-			return fmt.Errorf("zone edits returned status FAILED: successfully canceled zone edits")
+			return fmt.Errorf("zone edits returned status %s: successfully canceled zone edits", status)
+		case editStatusTerminal:
+			return fmt.Errorf("zone edits returned status %s", status)
 		}
 
-		time.Sleep(POLL_INTERVAL)
+		if err := sleepContext(ctx, c.retryDelay(attempt)); err != nil {
+			return fmt.Errorf("stopped waiting, edits may still be applied: %s", context.Cause(ctx))
+		}
 	}
 }
 
+type editStatusClass int
+
+const (
+	editStatusTransient editStatusClass = iota
+	editStatusCompleted
+	editStatusFailed
+	editStatusTerminal
+)
+
+// zoneEditStatusClasses classifies the statuses CSC reports for a zone edit.
+// FAILED edits are canceled before reporting the error; other terminal
+// statuses already leave nothing to cancel. Statuses not listed, including
+// PENDING, IN_PROGRESS and PARTIAL, are transient and keep being polled.
+var zoneEditStatusClasses = map[string]editStatusClass{
+	"PENDING":     editStatusTransient,
+	"IN_PROGRESS": editStatusTransient,
+	"PARTIAL":     editStatusTransient,
+	"COMPLETED":   editStatusCompleted,
+	"FAILED":      editStatusFailed,
+	"CANCELLED":   editStatusTerminal,
+	"CANCELED":    editStatusTerminal,
+	"REJECTED":    editStatusTerminal,
+}
+
+// fetchZoneEditStatus reads the status of a zone edit. At most
+// MaxConcurrentPolls status requests are in flight at once across all zones,
+// so a large batch does not trip CSC's rate limits.
+func (c *Client) fetchZoneEditStatus(ctx context.Context, editId string) (*ZoneEditStatus, int, error) {
+	select {
+	case c.pollSemaphore <- struct{}{}:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	defer func() { <-c.pollSemaphore }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(c.EditStatusPath, editId), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to create request: %s", err)
+	}
+
+	editStatusResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to send request: %s", err)
+	}
+	defer editStatusResp.Body.Close()
+
+	if editStatusResp.StatusCode != http.StatusOK {
+		return nil, editStatusResp.StatusCode, fmt.Errorf("edit status request failed: %w", zoneRequestError(editStatusResp))
+	}
+
+	var editStatusJson ZoneEditStatus
+	err = json.NewDecoder(editStatusResp.Body).Decode(&editStatusJson)
+	if err != nil {
+		return nil, editStatusResp.StatusCode, fmt.Errorf("unable to unmarshal edit status response: %s", err)
+	}
+
+	return &editStatusJson, editStatusResp.StatusCode, nil
+}
+
 func (c *Client) returnRecord(zone string, recordType string, key string, value string, record *ZoneRecord) error {
 	id := c.genId(zone, recordType, key, value)
 
+	// Each caller is answered exactly once, so drop its error channel too.
 	c.returnChannelsMutex.Lock()
 	returnChan, ok := c.returnChannels[id]
 	if ok {
 		delete(c.returnChannels, id)
+		delete(c.errorChannels, id)
 	}
 	c.returnChannelsMutex.Unlock()
 	if !ok {
@@ -354,6 +996,7 @@ func (c *Client) returnErrorByIdWithoutLock(id string, err error) error {
 
 	errorChan <- err
 	delete(c.errorChannels, id)
+	delete(c.returnChannels, id)
 	close(errorChan)
 	return nil
 }
@@ -365,59 +1008,121 @@ func (c *Client) returnError(zone string, recordType string, key string, value s
 	return c.returnErrorByIdWithoutLock(c.genId(zone, recordType, key, value), err)
 }
 
-func (c *Client) returnErrorToZone(zone string, err error) error {
+// returnErrorToActions answers the callers of actions still waiting with
+// err. Records whose channels a later caller has taken over are skipped.
+func (c *Client) returnErrorToActions(actions []*RecordAction, err error) error {
 	c.returnChannelsMutex.Lock()
 	defer c.returnChannelsMutex.Unlock()
 
 	var rErrs []error
 
-	for id := range c.errorChannels {
-		if strings.Split(id, ":")[0] == zone {
-			rErr := c.returnErrorByIdWithoutLock(id, err)
+	for _, action := range actions {
+		id := c.genId(action.ZoneName, action.RecordType, action.KeyId(), action.ValueId())
+		if !c.ownsChannels(action, id) {
+			continue
+		}
 
-			if rErr != nil {
-				rErrs = append(rErrs, rErr)
-			}
+		rErr := c.returnErrorByIdWithoutLock(id, err)
+		if rErr != nil {
+			rErrs = append(rErrs, rErr)
 		}
 	}
 
 	if len(rErrs) > 0 {
-		return fmt.Errorf("failed to return error to %d in zone %s: %s", len(rErrs), zone, err)
+		return fmt.Errorf("failed to return error to %d actions: %s", len(rErrs), err)
 	}
 
 	return nil
 }
 
-func (c *Client) returnErrorToZoneWithRecordType(zone string, recordType string, err error) error {
-	c.returnChannelsMutex.Lock()
-	defer c.returnChannelsMutex.Unlock()
+// adoptionSnapshot returns the zone as it stands before payload is
+// submitted, or nil when the payload adds nothing or the zone cannot be read.
+func (c *Client) adoptionSnapshot(payload ZoneEditReq) *Zone {
+	if !slices.ContainsFunc(payload.Edits, func(edit ZoneEdit) bool { return edit.Action == "ADD" }) {
+		return nil
+	}
 
-	var rErrs []error
+	zone, err := c.GetZone(payload.ZoneName)
+	if err != nil {
+		c.logf("[WARN] failed to read zone %s before editing it, records will not be adopted if the response is lost: %s", payload.ZoneName, err)
+		return nil
+	}
 
-	for id := range c.errorChannels {
-		idParts := strings.Split(id, ":")
+	return zone
+}
 
-		if idParts[0] == zone && idParts[1] == recordType {
-			rErr := c.returnErrorByIdWithoutLock(id, err)
+// newlyAdded returns the ADD edits in payload whose records are absent
+// from before.
+func (c *Client) newlyAdded(payload ZoneEditReq, before *Zone) []ZoneEdit {
+	var added []ZoneEdit
+	for _, edit := range payload.Edits {
+		if edit.Action != "ADD" {
+			continue
+		}
+		if c.findRecord(c.GetRecordsByType(before, edit.RecordType), edit.RecordType, edit.NewKey, edit.NewValue) == nil {
+			added = append(added, edit)
+		}
+	}
 
-			if rErr != nil {
-				rErrs = append(rErrs, rErr)
-			}
+	return added
+}
+
+// awaitLostEdit polls the zone after an edit whose response was lost until
+// every record the edit adds has appeared, returning the zone once they
+// have. CSC applies a zone edit as a whole, so until then it may still be
+// pending. It returns nil when there is nothing to adopt or the edit is not
+// seen within LOST_EDIT_POLLS reads.
+func (c *Client) awaitLostEdit(ctx context.Context, payload ZoneEditReq, before *Zone) *Zone {
+	if before == nil {
+		return nil
+	}
+	added := c.newlyAdded(payload, before)
+	if len(added) == 0 {
+		return nil
+	}
+
+	for attempt := 0; attempt < LOST_EDIT_POLLS; attempt++ {
+		zone, err := c.RefreshZone(ctx, payload.ZoneName)
+		if err == nil && !slices.ContainsFunc(added, func(edit ZoneEdit) bool {
+			return c.findRecord(c.GetRecordsByType(zone, edit.RecordType), edit.RecordType, edit.NewKey, edit.NewValue) == nil
+		}) {
+			return zone
+		}
+
+		if sleepContext(ctx, c.retryDelay(attempt)) != nil {
+			return nil
 		}
 	}
 
-	if len(rErrs) > 0 {
-		return fmt.Errorf("failed to return error to %d in zone %s: %s", len(rErrs), zone, err)
+	return nil
+}
+
+// returnAdoptedRecords returns the record for each ADD edit in the batch
+// that is now present in the zone but was absent from before. Other edits
+// are left for the caller to fail.
+func (c *Client) returnAdoptedRecords(zone *Zone, payload ZoneEditReq, before *Zone) error {
+	for _, edit := range c.newlyAdded(payload, before) {
+		records := c.GetRecordsByType(zone, edit.RecordType)
+		record := c.findRecord(records, edit.RecordType, edit.NewKey, edit.NewValue)
+		if record == nil {
+			continue
+		}
+
+		err := c.returnRecord(payload.ZoneName, edit.RecordType, edit.KeyId(), edit.ValueId(), record)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (c *Client) cancelZoneEdit(editId string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("zones/edits/%s", editId), nil)
+	req, err := http.NewRequest("DELETE", fmt.Sprintf(c.EditCancelPath, editId), nil)
 	if err != nil {
 		return fmt.Errorf("unable to create request: %s", err)
 	}
+	c.tagChange(req)
 
 	res, err := c.http.Do(req)
 	if err != nil {
@@ -435,7 +1140,33 @@ func (c *Client) cancelZoneEdit(editId string) error {
 		return fmt.Errorf("unable to unmarshal zone edit cancellation error: %s", err)
 	}
 
-	return fmt.Errorf("failed to cancel zone edit: %s: %s: %q", zeErr.Code, zeErr.Description, zeErr.Value)
+	return fmt.Errorf("failed to cancel zone edit: %s", zeErr.Error())
+}
+
+// tagChange adds the client's ChangeId, if any, to a write request.
+func (c *Client) tagChange(req *http.Request) {
+	if c.ChangeId != "" {
+		req.Header.Set(CHANGE_ID_HEADER, c.ChangeId)
+	}
+}
+
+// cachedZone is a zone read from CSC and when it was read.
+type cachedZone struct {
+	zone      *Zone
+	fetchedAt time.Time
+}
+
+// cachedRecords is one record type of a zone read from CSC and when it was
+// read.
+type cachedRecords struct {
+	records   []ZoneRecord
+	fetchedAt time.Time
+}
+
+// cacheFresh reports whether a cache entry read at fetchedAt may be reused:
+// caching is enabled and the entry is still within ZoneCacheTtl.
+func (c *Client) cacheFresh(fetchedAt time.Time) bool {
+	return !c.DisableCache && time.Since(fetchedAt) < c.ZoneCacheTtl
 }
 
 func (c *Client) invalidateZoneCache(zoneName string) {
@@ -443,54 +1174,342 @@ func (c *Client) invalidateZoneCache(zoneName string) {
 	defer c.cacheMutex.Unlock()
 
 	delete(c.zoneCache, zoneName)
+	delete(c.zoneRecordCache, zoneName)
+}
+
+// FetchZone reads a zone from CSC, bypassing the cache and refreshing it.
+func (c *Client) FetchZone(ctx context.Context, zoneName string) (*Zone, error) {
+	return c.fetchZone(ctx, zoneName)
 }
 
-func (c *Client) FetchZone(zoneName string) (*Zone, error) {
-	zoneResp, err := c.http.Get(fmt.Sprintf("zones/%s", zoneName))
+func (c *Client) fetchZone(ctx context.Context, zoneName string) (*Zone, error) {
+	zone, err := c.fetchZonePages(ctx, zoneName, "")
 	if err != nil {
-		return nil, fmt.Errorf("unable to send request: %s", err)
+		return nil, err
 	}
-	defer zoneResp.Body.Close()
 
-	var zone Zone
-	err = json.NewDecoder(zoneResp.Body).Decode(&zone)
-	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal zone: %s", err)
+	if c.DisableCache {
+		return zone, nil
 	}
 
 	c.cacheMutex.Lock()
-	c.zoneCache[zoneName] = &zone
+	c.zoneCache[zoneName] = cachedZone{zone: zone, fetchedAt: time.Now()}
+	delete(c.zoneRecordCache, zoneName)
 	c.cacheMutex.Unlock()
 
+	return zone, nil
+}
+
+// fetchZonePages reads every page of a zone, optionally restricted to one
+// record type.
+func (c *Client) fetchZonePages(ctx context.Context, zoneName string, recordType string) (*Zone, error) {
+	page, err := c.fetchZonePage(ctx, zoneName, recordType, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := page.Zone
+	for n := int64(2); n <= page.Meta.Pages; n++ {
+		next, err := c.fetchZonePage(ctx, zoneName, recordType, n)
+		if err != nil {
+			return nil, err
+		}
+
+		zone.appendRecords(&next.Zone)
+	}
+
 	return &zone, nil
 }
 
-func (c *Client) GetZone(zoneName string) (*Zone, error) {
+// zonePage is one page of a zone read. The meta envelope is the one CSC
+// returns when listing zones, whose meta.pages has been decoded since the
+// provider's first release; a zone read carrying it is taken to split its
+// record lists across that many pages, requested with ?page=N as for the
+// listing. A read without meta.pages is a single page.
+type zonePage struct {
+	Zone
+	Meta struct {
+		Pages int64 `json:"pages"`
+	} `json:"meta"`
+}
+
+func (c *Client) fetchZonePage(ctx context.Context, zoneName string, recordType string, page int64) (*zonePage, error) {
+	query := url.Values{}
+	if recordType != "" {
+		query.Set("recordType", recordType)
+	}
+	if page > 1 {
+		query.Set("page", fmt.Sprintf("%d", page))
+	}
+
+	zonePath := fmt.Sprintf(ZONE_PATH, zoneName)
+	if len(query) > 0 {
+		zonePath = fmt.Sprintf("%s?%s", zonePath, query.Encode())
+	}
+
+	zoneResp, err := c.getWithRetry(ctx, zonePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zoneResp.Body.Close()
+
+	if zoneResp.StatusCode == http.StatusUnauthorized || zoneResp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("failed to read zone %s: %w: status code %d", zoneName, ErrUnauthorized, zoneResp.StatusCode)
+	}
+	// Decoding an error body as a zone would cache an empty zone.
+	if zoneResp.StatusCode < 200 || zoneResp.StatusCode > 299 {
+		err := zoneRequestError(zoneResp)
+		if zoneNotFound(zoneResp, err) {
+			return nil, fmt.Errorf("%w: %s: %w", ErrZoneNotFound, zoneName, err)
+		}
+		if recordType != "" && zoneResp.StatusCode == http.StatusBadRequest && namesRecordTypeParameter(err) {
+			return nil, errRecordTypeFilterUnsupported
+		}
+		return nil, fmt.Errorf("failed to read zone %s: %w", zoneName, err)
+	}
+
+	var zp zonePage
+	err = json.NewDecoder(zoneResp.Body).Decode(&zp)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal zone page %d: %s", page, err)
+	}
+	c.decodeZone(&zp.Zone)
+
+	return &zp, nil
+}
+
+// namesRecordTypeParameter reports whether a zone read was rejected because
+// of the recordType parameter itself, as opposed to any other bad request.
+// No CSC document describes the filter, so only an error that names the
+// parameter in its value or description is taken to mean it is unsupported.
+func namesRecordTypeParameter(err error) bool {
+	var zeErr *ZoneEditErr
+	if !errors.As(err, &zeErr) {
+		return false
+	}
+
+	return zeErr.Value == "recordType" || strings.Contains(zeErr.Description, "recordType")
+}
+
+// getWithRetry performs a GET, retrying failures the RetryPolicy accepts.
+// Server errors are left to the transport, which has already retried them
+// IdempotentRetries times. The final response is returned whatever its
+// status code.
+func (c *Client) getWithRetry(ctx context.Context, path string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create request: %s", err)
+		}
+
+		resp, err := c.http.Do(req)
+		statusCode := 0
+		if err != nil {
+			err = fmt.Errorf("unable to send request: %s", err)
+		} else if resp.StatusCode != http.StatusOK {
+			statusCode = resp.StatusCode
+			err = fmt.Errorf("request returned unsuccessful status code %d", resp.StatusCode)
+		} else {
+			return resp, nil
+		}
+
+		retry, delay := false, time.Duration(0)
+		if statusCode < 500 {
+			retry, delay = c.shouldRetry(attempt, statusCode, err)
+		}
+		if !retry || ctx.Err() != nil {
+			if resp != nil {
+				return resp, nil
+			}
+			return nil, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("gave up retrying: %w", err)
+		}
+	}
+}
+
+// appendRecords adds the record lists of another page of the same zone.
+func (z *Zone) appendRecords(page *Zone) {
+	z.A = append(z.A, page.A...)
+	z.CNAME = append(z.CNAME, page.CNAME...)
+	z.AAAA = append(z.AAAA, page.AAAA...)
+	z.TXT = append(z.TXT, page.TXT...)
+	z.MX = append(z.MX, page.MX...)
+	z.NS = append(z.NS, page.NS...)
+	z.SRV = append(z.SRV, page.SRV...)
+	z.CAA = append(z.CAA, page.CAA...)
+	z.TLSA = append(z.TLSA, page.TLSA...)
+	z.HINFO = append(z.HINFO, page.HINFO...)
+	z.LOC = append(z.LOC, page.LOC...)
+	z.NAPTR = append(z.NAPTR, page.NAPTR...)
+}
+
+// FetchZoneRecords returns the zone's records of a single type. A cached
+// zone is filtered in place; otherwise only that type is requested from CSC
+// with ?recordType=, and the response is filtered again in case CSC ignored
+// it. The client falls back to reading whole zones only when CSC rejects the
+// request with a 400 that names the recordType parameter; any other error is
+// returned.
+func (c *Client) FetchZoneRecords(ctx context.Context, zoneName string, recordType string) ([]ZoneRecord, error) {
 	c.cacheMutex.RLock()
-	zone, ok := c.zoneCache[zoneName]
+	cached, ok := c.zoneCache[zoneName]
+	cachedType, typeOk := c.zoneRecordCache[zoneName][recordType]
 	c.cacheMutex.RUnlock()
+	ok = ok && c.cacheFresh(cached.fetchedAt)
+	typeOk = typeOk && c.cacheFresh(cachedType.fetchedAt)
 
 	if ok {
-		return zone, nil
+		return c.zoneRecordsByType(cached.zone, recordType)
+	}
+	if typeOk {
+		return cachedType.records, nil
 	}
 
-	res, err, _ := c.zoneGroup.Do(zoneName, func() (interface{}, error) {
-		zone, err := c.FetchZone(zoneName)
+	if c.recordTypeFilterUnsupported.Load() {
+		zone, err := c.getZone(ctx, zoneName)
 		if err != nil {
 			return nil, err
 		}
 
-		c.cacheMutex.Lock()
-		c.zoneCache[zoneName] = zone
-		c.cacheMutex.Unlock()
-		return zone, nil
+		return c.zoneRecordsByType(zone, recordType)
+	}
+
+	fetchCtx := context.WithoutCancel(ctx)
+	resChan := c.zoneGroup.DoChan(zoneName+"/"+recordType, func() (interface{}, error) {
+		return c.fetchZoneRecords(fetchCtx, zoneName, recordType)
 	})
 
+	select {
+	case res := <-resChan:
+		if res.Err != nil {
+			if errors.Is(res.Err, errRecordTypeFilterUnsupported) {
+				return c.FetchZoneRecords(ctx, zoneName, recordType)
+			}
+			return nil, res.Err
+		}
+
+		records, ok := res.Val.([]ZoneRecord)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T fetching %s records for zone %s", res.Val, recordType, zoneName)
+		}
+		return records, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out reading %s records for zone %s: %w", recordType, zoneName, ctx.Err())
+	}
+}
+
+func (c *Client) fetchZoneRecords(ctx context.Context, zoneName string, recordType string) ([]ZoneRecord, error) {
+	zone, err := c.fetchZonePages(ctx, zoneName, recordType)
+	if errors.Is(err, errRecordTypeFilterUnsupported) {
+		c.recordTypeFilterUnsupported.Store(true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := c.zoneRecordsByType(zone, recordType)
 	if err != nil {
 		return nil, err
 	}
 
-	zone, ok = res.(*Zone)
+	if c.DisableCache {
+		return records, nil
+	}
+
+	c.cacheMutex.Lock()
+	if c.zoneRecordCache[zoneName] == nil {
+		c.zoneRecordCache[zoneName] = make(map[string]cachedRecords)
+	}
+	c.zoneRecordCache[zoneName][recordType] = cachedRecords{records: records, fetchedAt: time.Now()}
+	c.cacheMutex.Unlock()
+
+	return records, nil
+}
+
+func (c *Client) zoneRecordsByType(zone *Zone, recordType string) ([]ZoneRecord, error) {
+	if !slices.Contains(SupportedRecordTypes(), recordType) {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+	records := c.GetRecordsByType(zone, recordType)
+
+	return records, nil
+}
+
+// RefreshZone drops any cached copy of the zone and fetches it again, for use
+// after changes made outside of Terraform.
+func (c *Client) RefreshZone(ctx context.Context, zoneName string) (*Zone, error) {
+	c.invalidateZoneCache(zoneName)
+
+	return c.fetchZone(ctx, zoneName)
+}
+
+// ListZones fetches every zone visible to the configured credentials. The
+// result bypasses the zone cache.
+func (c *Client) ListZones(ctx context.Context) ([]Zone, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "zones", nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %s", err)
+	}
+
+	zonesResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request: %s", err)
+	}
+	defer zonesResp.Body.Close()
+
+	var zones Zones
+	err = json.NewDecoder(zonesResp.Body).Decode(&zones)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal zones: %s", err)
+	}
+	for i := range zones.Zones {
+		c.decodeZone(&zones.Zones[i])
+	}
+
+	return zones.Zones, nil
+}
+
+func (c *Client) GetZone(zoneName string) (*Zone, error) {
+	return c.getZone(context.Background(), zoneName)
+}
+
+// getZone returns the cached zone or fetches it, collapsing concurrent
+// fetches of the same zone. The shared fetch is detached from ctx so one
+// caller giving up does not fail the others; ctx only bounds this caller's
+// wait.
+func (c *Client) getZone(ctx context.Context, zoneName string) (*Zone, error) {
+	c.cacheMutex.RLock()
+	cached, ok := c.zoneCache[zoneName]
+	c.cacheMutex.RUnlock()
+	ok = ok && c.cacheFresh(cached.fetchedAt)
+
+	if ok {
+		return cached.zone, nil
+	}
+
+	// fetchZone caches the zone it reads.
+	fetchCtx := context.WithoutCancel(ctx)
+	resChan := c.zoneGroup.DoChan(zoneName, func() (interface{}, error) {
+		return c.fetchZone(fetchCtx, zoneName)
+	})
+
+	var res singleflight.Result
+	select {
+	case res = <-resChan:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("gave up waiting for zone %s: %w", zoneName, ctx.Err())
+	}
+
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	zone, ok := res.Val.(*Zone)
 	if !ok {
 		return nil, fmt.Errorf("failed to assert type for *zone")
 	}
@@ -498,23 +1517,173 @@ func (c *Client) GetZone(zoneName string) (*Zone, error) {
 	return zone, nil
 }
 
-func (c *Client) GetRecordsByType(zone *Zone, recordType string) []ZoneRecord {
-	switch recordType {
-	case "A":
-		return zone.A
-	case "AAAA":
-		return zone.AAAA
-	case "CNAME":
-		return zone.CNAME
-	case "MX":
-		return zone.MX
-	case "NS":
-		return zone.NS
-	case "TXT":
-		return zone.TXT
-	default:
+// GetZones reads the named zones concurrently, returning them in the order
+// given. Cached zones are reused unless refresh is set, in which case each
+// zone is read live and the cache updated. Every zone is attempted; the
+// errors of any that fail are joined.
+func (c *Client) GetZones(ctx context.Context, zoneNames []string, refresh bool) ([]*Zone, error) {
+	zones := make([]*Zone, len(zoneNames))
+	errs := make([]error, len(zoneNames))
+
+	var wg sync.WaitGroup
+	for i, zoneName := range zoneNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if refresh {
+				zones[i], errs[i] = c.RefreshZone(ctx, zoneName)
+			} else {
+				zones[i], errs[i] = c.getZone(ctx, zoneName)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// ReadRecord looks up a single record by key for read-only callers such as
+// data sources. It shares the zone cache with the edit path but never touches
+// the edit queue, so it does not wait on the flush interval.
+func (c *Client) ReadRecord(ctx context.Context, zoneName string, recordType string, key string) (*ZoneRecord, error) {
+	records, err := c.FetchZoneRecords(ctx, zoneName, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	record := c.GetRecordByKey(records, key)
+	if record == nil {
+		return nil, fmt.Errorf("%w: record of type %s with key '%s' was not found in zone %s", ErrRecordNotFound, recordType, key, zoneName)
+	}
+
+	return record, nil
+}
+
+// ReadRecordById looks up a single record by id, reading only records of
+// its type when the zone is not already cached.
+func (c *Client) ReadRecordById(ctx context.Context, zoneName string, recordType string, id string) (*ZoneRecord, error) {
+	records, err := c.FetchZoneRecords(ctx, zoneName, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	record := c.GetRecordById(records, id)
+	if record == nil {
+		return nil, fmt.Errorf("%w: record of type %s with id '%s' was not found in zone %s", ErrRecordNotFound, recordType, id, zoneName)
+	}
+
+	return record, nil
+}
+
+// WaitForPropagation re-reads a record until CSC reports it as synced to the
+// zone's nameservers, backing off between reads. A record without a
+// propagation status is returned straight away, since there is nothing to
+// wait for.
+func (c *Client) WaitForPropagation(ctx context.Context, zoneName string, recordType string, id string) (*ZoneRecord, error) {
+	for attempt := 0; ; attempt++ {
+		c.invalidateZoneCache(zoneName)
+
+		record, err := c.ReadRecordById(ctx, zoneName, recordType, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if record.PropagationStatus == "" || strings.EqualFold(record.PropagationStatus, PROPAGATION_STATUS_SYNCED) {
+			return record, nil
+		}
+
+		if err := sleepContext(ctx, c.retryDelay(attempt)); err != nil {
+			return nil, fmt.Errorf("gave up waiting for %s record %s in zone %s to propagate, last status %s: %w",
+				recordType, id, zoneName, record.PropagationStatus, err)
+		}
+	}
+}
+
+// IsApexKey reports whether a record key refers to the zone apex.
+func IsApexKey(zoneName string, key string) bool {
+	key = strings.TrimSuffix(strings.ToLower(key), ".")
+
+	return key == "" || key == "@" || key == strings.TrimSuffix(strings.ToLower(zoneName), ".")
+}
+
+// CheckApexNs rejects NS records at the zone apex, which hold the zone's
+// delegation and are managed by CSC rather than as ordinary records. NS
+// records delegating a subdomain are allowed.
+func CheckApexNs(zoneName string, recordType string, key string) error {
+	if recordType != "NS" || !IsApexKey(zoneName, key) {
 		return nil
 	}
+
+	return fmt.Errorf("%w: refusing to change NS record '%s' in zone %s, change the zone's nameservers through CSC instead", ErrApexNs, key, zoneName)
+}
+
+// Nameservers returns the zone's apex NS targets, lower-cased, without
+// trailing dots, deduplicated and sorted.
+func (z *Zone) Nameservers() []string {
+	seen := make(map[string]bool)
+	var nameservers []string
+
+	for _, record := range z.NS {
+		if !IsApexKey(z.ZoneName, record.Key) {
+			continue
+		}
+
+		nameserver := strings.TrimSuffix(strings.ToLower(record.Value), ".")
+		if nameserver == "" || seen[nameserver] {
+			continue
+		}
+
+		seen[nameserver] = true
+		nameservers = append(nameservers, nameserver)
+	}
+
+	sort.Strings(nameservers)
+	return nameservers
+}
+
+// recordTypes lists the record types the client can manage, with the zone
+// field holding each. It is the source of truth for SupportedRecordTypes
+// and GetRecordsByType.
+var recordTypes = []struct {
+	name    string
+	records func(zone *Zone) []ZoneRecord
+}{
+	{"A", func(zone *Zone) []ZoneRecord { return zone.A }},
+	{"AAAA", func(zone *Zone) []ZoneRecord { return zone.AAAA }},
+	{"CNAME", func(zone *Zone) []ZoneRecord { return zone.CNAME }},
+	{"MX", func(zone *Zone) []ZoneRecord { return zone.MX }},
+	{"NS", func(zone *Zone) []ZoneRecord { return zone.NS }},
+	{"TXT", func(zone *Zone) []ZoneRecord { return zone.TXT }},
+	{"SRV", func(zone *Zone) []ZoneRecord { return zone.SRV }},
+	{"CAA", func(zone *Zone) []ZoneRecord { return zone.CAA }},
+	{"TLSA", func(zone *Zone) []ZoneRecord { return zone.TLSA }},
+}
+
+// SupportedRecordTypes returns the record types the client can manage.
+func SupportedRecordTypes() []string {
+	names := make([]string, len(recordTypes))
+	for i, recordType := range recordTypes {
+		names[i] = recordType.name
+	}
+
+	return names
+}
+
+// GetRecordsByType returns the zone's records of a type. It is nil both for
+// an unsupported type and for a supported one the zone holds no records of.
+func (c *Client) GetRecordsByType(zone *Zone, recordType string) []ZoneRecord {
+	for _, t := range recordTypes {
+		if t.name == recordType {
+			return t.records(zone)
+		}
+	}
+
+	return nil
 }
 
 func (c *Client) GetRecordByKey(records []ZoneRecord, key string) *ZoneRecord {
@@ -537,29 +1706,92 @@ func (c *Client) GetRecordById(records []ZoneRecord, id string) *ZoneRecord {
 	return nil
 }
 
-func (c *Client) GetRecordByTypeByKey(zone *Zone, recordType string, key string) (*ZoneRecord, error) {
+func (c *Client) GetRecordByKeyValue(records []ZoneRecord, key string, value string) *ZoneRecord {
+	for i, record := range records {
+		if record.Key == key && record.Value == value {
+			return &records[i]
+		}
+	}
+
+	return nil
+}
+
+// RecordValuesEqual reports whether two values of a record type are the
+// same. AAAA values are compared as addresses, so that forms such as
+// "2001:db8::1" and "2001:0db8:0:0:0:0:0:1" are equal, and CAA values by
+// their fields, so that `issue "ca.example"` and `0 issue "ca.example"` are
+// equal; other types are compared exactly.
+func RecordValuesEqual(recordType string, a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	switch recordType {
+	case "AAAA":
+		ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+		return ipA != nil && ipB != nil && ipA.Equal(ipB)
+	case "CAA":
+		caaA, errA := ParseCaaValue(a)
+		caaB, errB := ParseCaaValue(b)
+		return errA == nil && errB == nil && caaA.String() == caaB.String()
+	default:
+		return false
+	}
+}
+
+// findRecord returns the record with the key and a value equal to value by
+// RecordValuesEqual, preferring an exact match.
+func (c *Client) findRecord(records []ZoneRecord, recordType string, key string, value string) *ZoneRecord {
+	if record := c.GetRecordByKeyValue(records, key, value); record != nil {
+		return record
+	}
+
+	for i, record := range records {
+		if record.Key == key && RecordValuesEqual(recordType, record.Value, value) {
+			return &records[i]
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetRecordByTypeByKeyValue(zone *Zone, recordType string, key string, value string) (*ZoneRecord, error) {
+	if !slices.Contains(SupportedRecordTypes(), recordType) {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
 	records := c.GetRecordsByType(zone, recordType)
-	if records == nil {
+
+	record := c.findRecord(records, recordType, key, value)
+	if record == nil {
+		return nil, fmt.Errorf("%w: record of type %s with key '%s' and value '%s' was not found in zone %s", ErrRecordNotFound, recordType, key, value, zone.ZoneName)
+	}
+
+	return record, nil
+}
+
+func (c *Client) GetRecordByTypeByKey(zone *Zone, recordType string, key string) (*ZoneRecord, error) {
+	if !slices.Contains(SupportedRecordTypes(), recordType) {
 		return nil, fmt.Errorf("unsupported record type: %s", recordType)
 	}
+	records := c.GetRecordsByType(zone, recordType)
 
 	record := c.GetRecordByKey(records, key)
 	if record == nil {
-		return nil, fmt.Errorf("record of type %s with key '%s' was not found in zone %s", recordType, key, zone.ZoneName)
+		return nil, fmt.Errorf("%w: record of type %s with key '%s' was not found in zone %s", ErrRecordNotFound, recordType, key, zone.ZoneName)
 	}
 
 	return record, nil
 }
 
 func (c *Client) GetRecordByTypeById(zone *Zone, recordType string, id string) (*ZoneRecord, error) {
-	records := c.GetRecordsByType(zone, recordType)
-	if records == nil {
+	if !slices.Contains(SupportedRecordTypes(), recordType) {
 		return nil, fmt.Errorf("unsupported record type: %s", recordType)
 	}
+	records := c.GetRecordsByType(zone, recordType)
 
 	record := c.GetRecordById(records, id)
 	if record == nil {
-		return nil, fmt.Errorf("record of type %s with id '%s' was not found in zone %s", recordType, id, zone.ZoneName)
+		return nil, fmt.Errorf("%w: record of type %s with id '%s' was not found in zone %s", ErrRecordNotFound, recordType, id, zone.ZoneName)
 	}
 
 	return record, nil
@@ -0,0 +1,160 @@
+package cscdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DiffRecordSet returns the edits that turn current into desired, both
+// records of a single type. Records are matched by key and by value as
+// RecordValuesEqual compares them, so a record whose value is unchanged keeps
+// its CSC id: it is left alone when nothing differs and edited in place when
+// only its TTL or priority do. Only unmatched records are purged or added. A
+// desired TTL of zero leaves the current TTL as it is. Edits are returned
+// purges first, then edits, then adds, each in input order.
+func DiffRecordSet(recordType string, current []ZoneRecord, desired []ZoneRecord) []ZoneEdit {
+	matched := make([]bool, len(current))
+	match := func(want ZoneRecord) int {
+		for i, record := range current {
+			if !matched[i] && record.Key == want.Key && RecordValuesEqual(recordType, record.Value, want.Value) {
+				matched[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	var edits, adds []ZoneEdit
+	for _, want := range desired {
+		i := match(want)
+		if i < 0 {
+			adds = append(adds, ZoneEdit{
+				Action:      "ADD",
+				RecordType:  recordType,
+				NewKey:      want.Key,
+				NewValue:    want.Value,
+				NewTtl:      want.Ttl,
+				NewPriority: want.Priority,
+			})
+			continue
+		}
+
+		have := current[i]
+		ttl := want.Ttl
+		if ttl == 0 {
+			ttl = have.Ttl
+		}
+		if ttl == have.Ttl && want.Priority == have.Priority {
+			continue
+		}
+
+		edits = append(edits, ZoneEdit{
+			Action:          "EDIT",
+			RecordType:      recordType,
+			CurrentKey:      have.Key,
+			CurrentValue:    have.Value,
+			CurrentTtl:      have.Ttl,
+			CurrentPriority: have.Priority,
+			NewKey:          want.Key,
+			NewValue:        want.Value,
+			NewTtl:          ttl,
+			NewPriority:     want.Priority,
+		})
+	}
+
+	var purges []ZoneEdit
+	for i, record := range current {
+		if matched[i] {
+			continue
+		}
+
+		purges = append(purges, ZoneEdit{
+			Action:       "PURGE",
+			RecordType:   recordType,
+			CurrentKey:   record.Key,
+			CurrentValue: record.Value,
+		})
+	}
+
+	return append(append(purges, edits...), adds...)
+}
+
+// ReconcileRecordSet makes the zone's records of the given type and key
+// match desired, applying only the edits DiffRecordSet finds. The edits are
+// submitted together so they land in one batch. It returns the resulting
+// records in the order of desired.
+func (c *Client) ReconcileRecordSet(ctx context.Context, zoneName string, recordType string, key string, desired []ZoneRecord) ([]ZoneRecord, error) {
+	records, err := c.FetchZoneRecords(ctx, zoneName, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []ZoneRecord
+	for _, record := range records {
+		if record.Key == key {
+			current = append(current, record)
+		}
+	}
+
+	for _, want := range desired {
+		if want.Key != key {
+			return nil, fmt.Errorf("record set for key '%s' cannot hold a record with key '%s'", key, want.Key)
+		}
+	}
+
+	edits := DiffRecordSet(recordType, current, desired)
+
+	results := make([]*ZoneRecord, len(edits))
+	errs := make([]error, len(edits))
+	perform := func(i int) {
+		results[i], errs[i] = c.PerformRecordAction(ctx, &RecordAction{ZoneEdit: edits[i], ZoneName: zoneName})
+	}
+
+	if c.Synchronous {
+		for i := range edits {
+			perform(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i := range edits {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				perform(i)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to reconcile %s record set '%s' in zone %s: %w", recordType, key, zoneName, err)
+	}
+
+	// Records returned by the edits come ahead of the current records they
+	// replace, and each is matched to at most one desired record.
+	var candidates []ZoneRecord
+	for i, edit := range edits {
+		if edit.Action != "PURGE" && results[i] != nil {
+			candidates = append(candidates, *results[i])
+		}
+	}
+	candidates = append(candidates, current...)
+	used := make([]bool, len(candidates))
+
+	reconciled := make([]ZoneRecord, 0, len(desired))
+	for _, want := range desired {
+		var record ZoneRecord
+		for i, candidate := range candidates {
+			if !used[i] && candidate.Key == want.Key && RecordValuesEqual(recordType, candidate.Value, want.Value) {
+				used[i] = true
+				record = candidate
+				break
+			}
+		}
+		reconciled = append(reconciled, record)
+	}
+
+	return reconciled, nil
+}
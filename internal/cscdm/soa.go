@@ -0,0 +1,193 @@
+package cscdm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SoaEmailToMailbox converts an SOA responsible-person name in DNS notation,
+// such as "hostmaster.example.com.", to the mailbox it stands for,
+// "hostmaster@example.com". The first unescaped dot separates the local part
+// from the domain, and dots escaped as "\." belong to the local part.
+func SoaEmailToMailbox(name string) (string, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".")
+	if strings.ContainsAny(name, "@ ") {
+		return "", fmt.Errorf("SOA email %q is not in DNS notation", name)
+	}
+
+	var local strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch {
+		case name[i] == '\\' && i+1 < len(name):
+			i++
+			local.WriteByte(name[i])
+		case name[i] == '.':
+			return checkMailbox(local.String(), name[i+1:])
+		default:
+			local.WriteByte(name[i])
+		}
+	}
+
+	return "", fmt.Errorf("SOA email %q has no domain", name)
+}
+
+// MailboxToSoaEmail converts a mailbox such as "host.master@example.com" to
+// SOA DNS notation, "host\.master.example.com", escaping dots in the local
+// part. A name already in DNS notation is validated and returned without
+// its trailing dot.
+func MailboxToSoaEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		mailbox, err := SoaEmailToMailbox(email)
+		if err != nil {
+			return "", err
+		}
+		return MailboxToSoaEmail(mailbox)
+	}
+
+	mailbox, err := checkMailbox(local, strings.TrimSuffix(domain, "."))
+	if err != nil {
+		return "", err
+	}
+
+	local, domain, _ = strings.Cut(mailbox, "@")
+	return strings.ReplaceAll(local, ".", `\.`) + "." + domain, nil
+}
+
+// SoaEmailsEqual reports whether two SOA emails, in either notation, name the
+// same mailbox. Domains are compared case-insensitively.
+func SoaEmailsEqual(a string, b string) bool {
+	a, errA := MailboxToSoaEmail(a)
+	b, errB := MailboxToSoaEmail(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	localA, domainA := splitSoaEmail(a)
+	localB, domainB := splitSoaEmail(b)
+
+	return localA == localB && strings.EqualFold(domainA, domainB)
+}
+
+func splitSoaEmail(name string) (string, string) {
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '\\':
+			i++
+		case '.':
+			return name[:i], name[i+1:]
+		}
+	}
+
+	return name, ""
+}
+
+func checkMailbox(local string, domain string) (string, error) {
+	if local == "" || strings.ContainsAny(local, "@ ") {
+		return "", fmt.Errorf("SOA email has an invalid local part %q", local)
+	}
+	if domain == "" || strings.ContainsAny(domain, "@ \\") || strings.HasPrefix(domain, ".") || strings.Contains(domain, "..") {
+		return "", fmt.Errorf("SOA email has an invalid domain %q", domain)
+	}
+
+	return local + "@" + domain, nil
+}
+
+// ZoneSoaEdit holds the SOA fields to change in an edit of a zone's SOA
+// record. Fields left nil or empty are kept as they are.
+type ZoneSoaEdit struct {
+	Refresh   *int64 `json:"refresh,omitempty"`
+	Retry     *int64 `json:"retry,omitempty"`
+	Expire    *int64 `json:"expire,omitempty"`
+	TtlMin    *int64 `json:"ttlMin,omitempty"`
+	TechEmail string `json:"techEmail,omitempty"`
+}
+
+// PerformSoaEdit changes a zone's SOA record and returns it as CSC reports it
+// afterwards. The edit is submitted on its own, bypassing the record action
+// queue, but is otherwise handled like any other zone edit: it holds the
+// ZoneLocker, is retried under RetryPolicy and is waited on until complete.
+// A TechEmail given as a mailbox is sent in DNS notation.
+func (c *Client) PerformSoaEdit(ctx context.Context, zoneName string, soa ZoneSoaEdit) (*ZoneSoaRecord, error) {
+	if soa.TechEmail != "" {
+		techEmail, err := MailboxToSoaEmail(soa.TechEmail)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidZoneEdit, err)
+		}
+		soa.TechEmail = techEmail
+	}
+
+	payload := ZoneEditReq{
+		ZoneName: zoneName,
+		Edits:    []ZoneEdit{{RecordType: "SOA", Action: "EDIT", NewSoa: &soa}},
+	}
+
+	if c.ZoneLocker != nil {
+		unlock, err := c.ZoneLocker.Lock(ctx, zoneName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock zone %s: %s", zoneName, err)
+		}
+		defer unlock()
+	}
+
+	if c.EditPreviewPath != "" {
+		if err := c.writeEditPreview(payload); err != nil {
+			c.logf("failed to record edit preview: %s", err.Error())
+		}
+	}
+
+	editId, err := c.editZone(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit zone %s SOA: %w", zoneName, err)
+	}
+	if err := c.waitForZoneEdits(ctx, *editId); err != nil {
+		return nil, fmt.Errorf("failed to wait for %s SOA edit: %s", zoneName, err)
+	}
+
+	c.invalidateZoneCache(zoneName)
+	zone, err := c.fetchZone(ctx, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zone.SOA, nil
+}
+
+// ClaimSoa records that the caller manages a zone's SOA, returning an error
+// wrapping ErrSoaClaimed when it is already claimed, so that two resources
+// do not fight over the same SOA. Claims last until ReleaseSoa.
+func (c *Client) ClaimSoa(zoneName string) error {
+	c.soaClaimsMutex.Lock()
+	defer c.soaClaimsMutex.Unlock()
+
+	name := soaClaimName(zoneName)
+	if c.soaClaims[name] {
+		return fmt.Errorf("%w: zone %s", ErrSoaClaimed, zoneName)
+	}
+	c.soaClaims[name] = true
+
+	return nil
+}
+
+// SoaClaimed reports whether a zone's SOA is claimed, without claiming it.
+func (c *Client) SoaClaimed(zoneName string) bool {
+	c.soaClaimsMutex.Lock()
+	defer c.soaClaimsMutex.Unlock()
+
+	return c.soaClaims[soaClaimName(zoneName)]
+}
+
+// ReleaseSoa drops a claim made with ClaimSoa.
+func (c *Client) ReleaseSoa(zoneName string) {
+	c.soaClaimsMutex.Lock()
+	defer c.soaClaimsMutex.Unlock()
+
+	delete(c.soaClaims, soaClaimName(zoneName))
+}
+
+func soaClaimName(zoneName string) string {
+	return strings.TrimSuffix(strings.ToLower(zoneName), ".")
+}
@@ -0,0 +1,72 @@
+package cscdm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TlsaValue is the structured form of a TLSA record value, written by CSC as
+// "<usage> <selector> <matching type> <certificate association data>".
+type TlsaValue struct {
+	Usage           int64
+	Selector        int64
+	MatchingType    int64
+	CertificateData string
+}
+
+// ParseTlsaValue splits a TLSA record value into its fields, validating the
+// ranges defined in RFC 6698.
+func ParseTlsaValue(value string) (*TlsaValue, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("TLSA value must be '<usage> <selector> <matching type> <data>', got %q", value)
+	}
+
+	var numbers [3]int64
+	for i, name := range []string{"usage", "selector", "matching type"} {
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("TLSA %s must be a number, got %q", name, fields[i])
+		}
+		numbers[i] = n
+	}
+
+	tlsa := &TlsaValue{
+		Usage:           numbers[0],
+		Selector:        numbers[1],
+		MatchingType:    numbers[2],
+		CertificateData: fields[3],
+	}
+
+	return tlsa, tlsa.Validate()
+}
+
+// Validate checks the numeric parameters and that the association data is hex.
+func (t *TlsaValue) Validate() error {
+	if t.Usage < 0 || t.Usage > 3 {
+		return fmt.Errorf("TLSA usage must be between 0 and 3, got %d", t.Usage)
+	}
+	if t.Selector < 0 || t.Selector > 1 {
+		return fmt.Errorf("TLSA selector must be 0 or 1, got %d", t.Selector)
+	}
+	if t.MatchingType < 0 || t.MatchingType > 2 {
+		return fmt.Errorf("TLSA matching type must be between 0 and 2, got %d", t.MatchingType)
+	}
+
+	if t.CertificateData == "" {
+		return fmt.Errorf("TLSA certificate association data must not be empty")
+	}
+	for _, r := range t.CertificateData {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return fmt.Errorf("TLSA certificate association data must be hexadecimal, got %q", t.CertificateData)
+		}
+	}
+
+	return nil
+}
+
+// String assembles the value in the form CSC expects.
+func (t *TlsaValue) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector, t.MatchingType, strings.ToLower(t.CertificateData))
+}
@@ -0,0 +1,104 @@
+package cscdm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValueTransform rewrites record values between the form written in
+// Terraform configuration and the form stored by CSC. Encode is applied to
+// values sent to CSC and Decode to values read back, so state holds what
+// the user configured. Decode reports false for values it cannot have
+// produced, such as records created outside of Terraform, which are then
+// read as stored.
+type ValueTransform interface {
+	Encode(recordType string, value string) string
+	Decode(recordType string, value string) (string, bool)
+}
+
+// IdentityTransform leaves every value unchanged. It is the default.
+type IdentityTransform struct{}
+
+func (IdentityTransform) Encode(recordType string, value string) string {
+	return value
+}
+
+func (IdentityTransform) Decode(recordType string, value string) (string, bool) {
+	return value, true
+}
+
+// AffixTransform wraps values of the given record types, or of every type
+// when RecordTypes is empty, in a fixed prefix and suffix.
+type AffixTransform struct {
+	Prefix      string
+	Suffix      string
+	RecordTypes []string
+}
+
+func (t AffixTransform) applies(recordType string) bool {
+	return len(t.RecordTypes) == 0 || slices.Contains(t.RecordTypes, recordType)
+}
+
+func (t AffixTransform) Encode(recordType string, value string) string {
+	if !t.applies(recordType) {
+		return value
+	}
+
+	return t.Prefix + value + t.Suffix
+}
+
+func (t AffixTransform) Decode(recordType string, value string) (string, bool) {
+	if !t.applies(recordType) {
+		return value, true
+	}
+	if len(value) < len(t.Prefix)+len(t.Suffix) || !strings.HasPrefix(value, t.Prefix) || !strings.HasSuffix(value, t.Suffix) {
+		return value, false
+	}
+
+	return value[len(t.Prefix) : len(value)-len(t.Suffix)], true
+}
+
+// CheckValueTransform returns an error wrapping ErrAsymmetricValueTransform
+// when the client's ValueTransform would not read value back as written,
+// which would otherwise show up as a permanent diff.
+func (c *Client) CheckValueTransform(recordType string, value string) error {
+	encoded := c.ValueTransform.Encode(recordType, value)
+	decoded, ok := c.ValueTransform.Decode(recordType, encoded)
+	if !ok || decoded != value {
+		return fmt.Errorf("%w: %s value %q is stored as %q but read back as %q", ErrAsymmetricValueTransform, recordType, value, encoded, decoded)
+	}
+
+	return nil
+}
+
+// encodeZoneEdits returns a copy of payload with its values encoded for
+// CSC. The original is left in configured form, which results are matched
+// against once the zone is read back and decoded.
+func (c *Client) encodeZoneEdits(payload ZoneEditReq) ZoneEditReq {
+	edits := make([]ZoneEdit, len(payload.Edits))
+	for i, edit := range payload.Edits {
+		if edit.CurrentValue != "" {
+			edit.CurrentValue = c.ValueTransform.Encode(edit.RecordType, edit.CurrentValue)
+		}
+		if edit.NewValue != "" {
+			edit.NewValue = c.ValueTransform.Encode(edit.RecordType, edit.NewValue)
+		}
+		edits[i] = edit
+	}
+
+	return ZoneEditReq{ZoneName: payload.ZoneName, Edits: edits}
+}
+
+// decodeZone decodes the values of a zone read from CSC in place. Values the
+// transform cannot decode are left as stored.
+func (c *Client) decodeZone(zone *Zone) {
+	for _, t := range recordTypes {
+		records := t.records(zone)
+		for i := range records {
+			if decoded, ok := c.ValueTransform.Decode(t.name, records[i].Value); ok {
+				records[i].Value = decoded
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package cscdm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ZoneEditValidation is the verdict on one edit of a validated request. Err
+// is nil when the edit is expected to apply.
+type ZoneEditValidation struct {
+	Edit ZoneEdit
+	Err  error
+}
+
+// zoneEditValidateRes is CSC's response to a validation request, holding one
+// result per submitted edit in the order submitted.
+type zoneEditValidateRes struct {
+	Edits []struct {
+		Valid bool `json:"valid"`
+		ZoneEditErr
+	} `json:"edits"`
+}
+
+// ValidateZoneEdits checks a zone edit request without applying it. Each
+// edit is first checked locally, including against the request's other
+// edits with CheckCnameExclusivity; when EditValidatePath is set, those that
+// pass are then submitted there. Verdicts on individual edits are in the
+// results, in the order of req.Edits. The returned error is for the remote
+// check as a whole, alongside results holding the local verdicts. A missing
+// endpoint is remembered for the life of the client.
+func (c *Client) ValidateZoneEdits(ctx context.Context, req ZoneEditReq) ([]ZoneEditValidation, error) {
+	results := make([]ZoneEditValidation, len(req.Edits))
+	var remote ZoneEditReq
+	var remoteIndexes []int
+	cnameErrs := CheckCnameExclusivity(req.ZoneName, req.Edits)
+	for i, edit := range req.Edits {
+		results[i] = ZoneEditValidation{Edit: edit, Err: c.ValidateZoneEdit(req.ZoneName, edit)}
+		if results[i].Err == nil {
+			results[i].Err = cnameErrs[i]
+		}
+		if results[i].Err == nil {
+			remote.Edits = append(remote.Edits, edit)
+			remoteIndexes = append(remoteIndexes, i)
+		}
+	}
+
+	if len(remote.Edits) == 0 || c.EditValidatePath == "" || c.editValidationUnsupported.Load() {
+		return results, nil
+	}
+
+	remote.ZoneName = req.ZoneName
+	errs, err := c.validateRemotely(ctx, c.encodeZoneEdits(remote))
+	if err != nil {
+		return results, err
+	}
+	for i, err := range errs {
+		results[remoteIndexes[i]].Err = err
+	}
+
+	return results, nil
+}
+
+// validateRemotely submits edits to the validation endpoint, returning an
+// error per edit. Any unsuccessful status fails the request as a whole, and
+// a missing endpoint also disables remote validation.
+func (c *Client) validateRemotely(ctx context.Context, payload ZoneEditReq) ([]error, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal zone edit validation: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.EditValidatePath, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %s", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		c.editValidationUnsupported.Store(true)
+		c.logf("[WARN] zone edit validation is not offered at %s (status code %d), falling back to local checks", c.EditValidatePath, resp.StatusCode)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var zeErr ZoneEditErr
+		if json.Unmarshal(respBody, &zeErr) == nil && zeErr.Code != "" {
+			return nil, fmt.Errorf("validation request returned error with status code %d: %w", resp.StatusCode, &zeErr)
+		}
+		return nil, fmt.Errorf("validation request returned unsuccessful status code %d", resp.StatusCode)
+	}
+
+	var validateJson zoneEditValidateRes
+	if err := json.Unmarshal(respBody, &validateJson); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal zone edit validation response: %s", err)
+	}
+	if len(validateJson.Edits) != len(payload.Edits) {
+		return nil, fmt.Errorf("zone edit validation returned %d result(s) for %d edit(s)", len(validateJson.Edits), len(payload.Edits))
+	}
+
+	errs := make([]error, len(payload.Edits))
+	for i, result := range validateJson.Edits {
+		if !result.Valid {
+			zeErr := result.ZoneEditErr
+			errs[i] = &zeErr
+		}
+	}
+
+	return errs, nil
+}
+
+// ValidateZoneEdit checks an edit for mistakes that can be caught without
+// asking CSC, returning an error wrapping ErrInvalidZoneEdit, or one of the
+// more specific sentinels, for the first it finds.
+func (c *Client) ValidateZoneEdit(zoneName string, edit ZoneEdit) error {
+	if !slices.Contains(SupportedRecordTypes(), edit.RecordType) {
+		return fmt.Errorf("%w: unsupported record type %s", ErrInvalidZoneEdit, edit.RecordType)
+	}
+	if edit.Action == "ADD" || edit.Action == "PURGE" {
+		if err := CheckApexNs(zoneName, edit.RecordType, edit.KeyId()); err != nil {
+			return err
+		}
+	}
+	if edit.Action == "PURGE" {
+		return nil
+	}
+
+	if edit.RecordType == "CNAME" && IsApexKey(zoneName, edit.NewKey) {
+		return fmt.Errorf("%w: a CNAME record cannot be placed at the apex of zone %s", ErrInvalidZoneEdit, zoneName)
+	}
+
+	switch edit.RecordType {
+	case "A":
+		if ip := net.ParseIP(edit.NewValue); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("%w: A record value %q is not an IPv4 address", ErrInvalidZoneEdit, edit.NewValue)
+		}
+	case "AAAA":
+		if ip := net.ParseIP(edit.NewValue); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("%w: AAAA record value %q is not an IPv6 address", ErrInvalidZoneEdit, edit.NewValue)
+		}
+	case "TLSA":
+		if _, err := ParseTlsaValue(edit.NewValue); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidZoneEdit, err)
+		}
+	case "CAA":
+		if _, err := ParseCaaValue(edit.NewValue); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidZoneEdit, err)
+		}
+	}
+
+	return c.CheckValueTransform(edit.RecordType, edit.NewValue)
+}
+
+// CheckCnameExclusivity checks the records a set of edits adds or keeps for
+// the DNS rule that a CNAME is the only record at its key. It looks only at
+// the edits themselves, not at records already in the zone. The result holds
+// an error wrapping ErrCnameConflict for each conflicting edit, naming the
+// others at its key, and nil for the rest. Keys are compared
+// case-insensitively, with every form of the apex treated alike.
+func CheckCnameExclusivity(zoneName string, edits []ZoneEdit) []error {
+	byKey := make(map[string][]int)
+	var keys []string
+	for i, edit := range edits {
+		if edit.Action == "PURGE" {
+			continue
+		}
+
+		key := strings.TrimSuffix(strings.ToLower(edit.NewKey), ".")
+		if IsApexKey(zoneName, key) {
+			key = "@"
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], i)
+	}
+
+	errs := make([]error, len(edits))
+	for _, key := range keys {
+		indexes := byKey[key]
+		if len(indexes) < 2 || !slices.ContainsFunc(indexes, func(i int) bool { return edits[i].RecordType == "CNAME" }) {
+			continue
+		}
+
+		for _, i := range indexes {
+			var others []string
+			for _, j := range indexes {
+				if j != i {
+					others = append(others, fmt.Sprintf("%s '%s' %q", edits[j].RecordType, edits[j].NewKey, edits[j].NewValue))
+				}
+			}
+			errs[i] = fmt.Errorf("%w: %s record '%s' conflicts with %s", ErrCnameConflict, edits[i].RecordType, edits[i].NewKey, strings.Join(others, ", "))
+		}
+	}
+
+	return errs
+}
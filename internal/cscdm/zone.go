@@ -0,0 +1,110 @@
+package cscdm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// ZONES_PATH is the endpoint zones are created at.
+	ZONES_PATH = "zones"
+	// ZONE_PATH is the endpoint a zone is read and deleted at, with %s
+	// standing for the zone name.
+	ZONE_PATH = "zones/%s"
+)
+
+// ZoneCreateReq is the request body for creating a zone.
+type ZoneCreateReq struct {
+	ZoneName    string `json:"zoneName"`
+	HostingType string `json:"hostingType,omitempty"`
+}
+
+// CreateZone creates a zone, leaving the hosting type to CSC's default when
+// hostingType is empty, and returns the zone as CSC reports it afterwards,
+// including the SOA CSC assigned.
+func (c *Client) CreateZone(ctx context.Context, zoneName string, hostingType string) (*Zone, error) {
+	body, err := json.Marshal(ZoneCreateReq{ZoneName: zoneName, HostingType: hostingType})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal zone payload: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", ZONES_PATH, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.tagChange(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w: %s", ErrOutcomeUnknown, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create zone %s: %w", zoneName, zoneRequestError(resp))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	c.invalidateZoneCache(zoneName)
+
+	return c.fetchZone(ctx, zoneName)
+}
+
+// DeleteZone deletes a zone and drops it from the cache. A zone CSC reports
+// it does not know, see zoneNotFound, returns an error wrapping
+// ErrZoneNotFound.
+func (c *Client) DeleteZone(ctx context.Context, zoneName string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf(ZONE_PATH, zoneName), nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %s", err)
+	}
+	c.tagChange(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w: %s", ErrOutcomeUnknown, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		_, _ = io.Copy(io.Discard, resp.Body)
+	default:
+		err := zoneRequestError(resp)
+		if zoneNotFound(resp, err) {
+			c.invalidateZoneCache(zoneName)
+			return fmt.Errorf("%w: %s: %w", ErrZoneNotFound, zoneName, err)
+		}
+		return fmt.Errorf("failed to delete zone %s: %w", zoneName, err)
+	}
+
+	c.invalidateZoneCache(zoneName)
+
+	return nil
+}
+
+// zoneRequestError describes an unsuccessful response to a zone request,
+// using the error CSC reports in the body when there is one.
+func zoneRequestError(resp *http.Response) error {
+	var zeErr ZoneEditErr
+	if err := json.NewDecoder(resp.Body).Decode(&zeErr); err == nil && zeErr.Code != "" {
+		return fmt.Errorf("request returned error with status code %d: %w", resp.StatusCode, &zeErr)
+	}
+
+	return fmt.Errorf("request returned unsuccessful status code %d", resp.StatusCode)
+}
+
+// zoneNotFound reports whether a zone request failed because the zone does
+// not exist: a 404 whose body, decoded into err by zoneRequestError, is a CSC
+// error. A bare 404, such as one from a proxy or a wrong API URL, is not
+// taken to mean the zone is gone.
+func zoneNotFound(resp *http.Response, err error) bool {
+	var zeErr *ZoneEditErr
+	return resp.StatusCode == http.StatusNotFound && errors.As(err, &zeErr)
+}
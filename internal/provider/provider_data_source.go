@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-cscdm/internal/cscdm"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource = &ProviderDataSource{}
+)
+
+// NewProviderDataSource returns a constructor for a data source reporting
+// the given provider version. The version is captured here rather than passed
+// through ProviderData so the data source works without any credentials.
+func NewProviderDataSource(version string) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &ProviderDataSource{version: version}
+	}
+}
+
+// ProviderDataSource reports details of the provider build itself.
+type ProviderDataSource struct {
+	version string
+}
+
+type ProviderDataSourceModel struct {
+	Version    types.String `tfsdk:"version"`
+	ApiVersion types.String `tfsdk:"api_version"`
+}
+
+func (d *ProviderDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider"
+}
+
+func (d *ProviderDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Description: "Version of the provider build, `dev` for local builds.",
+				Computed:    true,
+			},
+			"api_version": schema.StringAttribute{
+				Description: "CSC Domain Manager API version the provider is built for, e.g. `v2`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ProviderDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := ProviderDataSourceModel{
+		Version:    types.StringValue(d.version),
+		ApiVersion: types.StringValue(cscdm.CSC_API_VERSION),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,126 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProvider_RegistersResourcesAndDataSources(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(provider.New("test")())()
+	if err != nil {
+		t.Fatalf("Failed to create provider server: %s", err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema failed: %s", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("GetProviderSchema returned error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	for _, name := range []string{"cscdm_record", "cscdm_zone", "cscdm_soa"} {
+		if _, ok := resp.ResourceSchemas[name]; !ok {
+			t.Errorf("Expected resource %s to be registered", name)
+		}
+	}
+
+	for _, name := range []string{"cscdm_zones", "cscdm_record", "cscdm_record_by_id", "cscdm_zone_records", "cscdm_zone_hcl", "cscdm_provider"} {
+		if _, ok := resp.DataSourceSchemas[name]; !ok {
+			t.Errorf("Expected data source %s to be registered", name)
+		}
+	}
+}
+
+func TestProvider_SupportedRecordTypesFunction(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(provider.New("test")())()
+	if err != nil {
+		t.Fatalf("Failed to create provider server: %s", err)
+	}
+
+	resp, err := server.CallFunction(context.Background(), &tfprotov6.CallFunctionRequest{Name: "supported_record_types"})
+	if err != nil {
+		t.Fatalf("CallFunction failed: %s", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("supported_record_types returned error: %s", resp.Error.Text)
+	}
+
+	listType := tftypes.List{ElementType: tftypes.String}
+	value, err := resp.Result.Unmarshal(listType)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal result: %s", err)
+	}
+
+	var elements []tftypes.Value
+	if err := value.As(&elements); err != nil {
+		t.Fatalf("Failed to read result list: %s", err)
+	}
+
+	var got []string
+	for _, element := range elements {
+		var recordType string
+		if err := element.As(&recordType); err != nil {
+			t.Fatalf("Failed to read record type: %s", err)
+		}
+		got = append(got, recordType)
+	}
+
+	if want := cscdm.SupportedRecordTypes(); !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestProvider_RejectedCredentialsSuggestSwapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	p := provider.New("test")()
+
+	var schemaResp fwprovider.SchemaResponse
+	p.Schema(ctx, fwprovider.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	configState := tfsdk.State(config)
+	if diags := configState.Set(ctx, &provider.CscDomainManagerProviderModel{
+		ApiKey:            types.StringValue("test-key"),
+		ApiToken:          types.StringValue("test-token"),
+		ApiUrl:            types.StringValue(server.URL + "/"),
+		VerifyCredentials: types.BoolValue(true),
+	}); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+	config.Raw = configState.Raw
+
+	var resp fwprovider.ConfigureResponse
+	p.Configure(ctx, fwprovider.ConfigureRequest{Config: config}, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected rejected credentials to fail configuration")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "not been swapped") {
+		t.Errorf("Expected the error to suggest checking for swapped credentials, got %q", detail)
+	}
+}
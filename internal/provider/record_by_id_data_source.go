@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-cscdm/internal/cscdm"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &RecordByIdDataSource{}
+	_ datasource.DataSourceWithConfigure = &RecordByIdDataSource{}
+)
+
+func NewRecordByIdDataSource() datasource.DataSource {
+	return &RecordByIdDataSource{}
+}
+
+// RecordByIdDataSource looks up a single record by its CSC id, the same
+// identity used when importing a cscdm_record.
+type RecordByIdDataSource struct {
+	client *cscdm.Client
+}
+
+type RecordByIdDataSourceModel struct {
+	Zone     types.String `tfsdk:"zone"`
+	Type     types.String `tfsdk:"type"`
+	Id       types.String `tfsdk:"id"`
+	UseCache types.Bool   `tfsdk:"use_cache"`
+	Key      types.String `tfsdk:"key"`
+	Value    types.String `tfsdk:"value"`
+	Ttl      types.Int64  `tfsdk:"ttl"`
+	Priority types.Int64  `tfsdk:"priority"`
+	Status   types.String `tfsdk:"status"`
+
+	PropagationStatus types.String `tfsdk:"propagation_status"`
+}
+
+func (d *RecordByIdDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record_by_id"
+}
+
+func (d *RecordByIdDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Required: true,
+			},
+			"type": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(cscdm.SupportedRecordTypes()...),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "CSC id of the record, as used when importing a `cscdm_record`.",
+				Required:    true,
+			},
+			"use_cache": schema.BoolAttribute{
+				Description: "Reuse a zone already cached by the provider during this run instead of reading it live. Defaults to `false`.",
+				Optional:    true,
+			},
+			"key": schema.StringAttribute{
+				Computed: true,
+			},
+			"value": schema.StringAttribute{
+				Computed: true,
+			},
+			"ttl": schema.Int64Attribute{
+				Computed: true,
+			},
+			"priority": schema.Int64Attribute{
+				Computed: true,
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+			"propagation_status": schema.StringAttribute{
+				Description: "Whether CSC reports the record as served by the zone's nameservers, such as `PENDING` or `SYNCED`. " +
+					"Null when CSC does not report it.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *RecordByIdDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RecordByIdDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state RecordByIdDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RECORD_DATA_SOURCE_DEFAULT_TIMEOUT)
+	defer cancel()
+
+	var zone *cscdm.Zone
+	var err error
+	if state.UseCache.ValueBool() {
+		zone, err = d.client.GetZone(state.Zone.ValueString())
+	} else {
+		zone, err = d.client.RefreshZone(ctx, state.Zone.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zone, got error: %s", err))
+		return
+	}
+
+	record, err := d.client.GetRecordByTypeById(zone, state.Type.ValueString(), state.Id.ValueString())
+	if errors.Is(err, cscdm.ErrRecordNotFound) {
+		resp.Diagnostics.AddError(
+			"Record Not Found",
+			fmt.Sprintf("No %s record with id %q exists in zone %s. The record may have been deleted or recreated under a new id.",
+				state.Type.ValueString(), state.Id.ValueString(), state.Zone.ValueString()),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read record, got error: %s", err))
+		return
+	}
+
+	state.Key = types.StringValue(record.Key)
+	state.Value = types.StringValue(record.Value)
+	state.Ttl = types.Int64Value(record.Ttl)
+	state.Priority = types.Int64Value(record.Priority)
+	state.Status = types.StringValue(record.Status)
+	state.PropagationStatus = types.StringNull()
+	if record.PropagationStatus != "" {
+		state.PropagationStatus = types.StringValue(record.PropagationStatus)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,81 @@
+package provider_test
+
+import (
+	"context"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func readRecordById(t *testing.T, client *cscdm.Client, recordType string, id string) (provider.RecordByIdDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+
+	ctx := context.Background()
+	d := provider.NewRecordByIdDataSource()
+	configurable, ok := d.(datasource.DataSourceWithConfigure)
+	if !ok {
+		t.Fatal("NewRecordByIdDataSource does not support Configure")
+	}
+	configurable.Configure(ctx, datasource.ConfigureRequest{ProviderData: client}, &datasource.ConfigureResponse{})
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	configState := tfsdk.State(config)
+	if diags := configState.Set(ctx, &provider.RecordByIdDataSourceModel{
+		Zone:     types.StringValue("example.com"),
+		Type:     types.StringValue(recordType),
+		Id:       types.StringValue(id),
+		UseCache: types.BoolNull(),
+		Key:      types.StringNull(),
+		Value:    types.StringNull(),
+		Ttl:      types.Int64Null(),
+		Priority: types.Int64Null(),
+		Status:   types.StringNull(),
+	}); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+	config.Raw = configState.Raw
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+
+	var state provider.RecordByIdDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		if diags := resp.State.Get(ctx, &state); diags.HasError() {
+			t.Fatalf("Failed to read state: %v", diags)
+		}
+	}
+
+	return state, resp.Diagnostics
+}
+
+func TestRecordByIdDataSource_ReadsRecordById(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300, Status: "ACTIVE"}},
+	})
+
+	state, diags := readRecordById(t, client, "A", "101")
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+	if state.Key.ValueString() != "www" || state.Value.ValueString() != "10.0.0.1" || state.Ttl.ValueInt64() != 300 {
+		t.Errorf("Expected record www -> 10.0.0.1 with TTL 300, got %+v", state)
+	}
+
+	_, diags = readRecordById(t, client, "A", "999")
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Record Not Found" {
+		t.Errorf("Expected a Record Not Found error for a missing id, got: %v", diags)
+	}
+}
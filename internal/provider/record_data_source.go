@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-cscdm/internal/cscdm"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	RECORD_DATA_SOURCE_DEFAULT_TIMEOUT = 30 * time.Second
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &RecordDataSource{}
+	_ datasource.DataSourceWithConfigure = &RecordDataSource{}
+)
+
+func NewRecordDataSource() datasource.DataSource {
+	return &RecordDataSource{}
+}
+
+// RecordDataSource looks up a single record by key.
+type RecordDataSource struct {
+	client *cscdm.Client
+}
+
+type RecordDataSourceModel struct {
+	Zone     types.String `tfsdk:"zone"`
+	Type     types.String `tfsdk:"type"`
+	Key      types.String `tfsdk:"key"`
+	Timeout  types.String `tfsdk:"timeout"`
+	UseCache types.Bool   `tfsdk:"use_cache"`
+	Id       types.String `tfsdk:"id"`
+	Value    types.String `tfsdk:"value"`
+	Ttl      types.Int64  `tfsdk:"ttl"`
+	Priority types.Int64  `tfsdk:"priority"`
+	Status   types.String `tfsdk:"status"`
+
+	PropagationStatus types.String `tfsdk:"propagation_status"`
+}
+
+func (d *RecordDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record"
+}
+
+func (d *RecordDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Required: true,
+			},
+			"type": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(cscdm.SupportedRecordTypes()...),
+				},
+			},
+			"key": schema.StringAttribute{
+				Required: true,
+			},
+			"timeout": schema.StringAttribute{
+				Description: "Maximum time to wait for the zone to be read, as a duration string. Defaults to `30s`.",
+				Optional:    true,
+			},
+			"use_cache": schema.BoolAttribute{
+				Description: "Reuse a zone already cached by the provider during this run instead of reading it live. Defaults to `false`.",
+				Optional:    true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"value": schema.StringAttribute{
+				Computed: true,
+			},
+			"ttl": schema.Int64Attribute{
+				Computed: true,
+			},
+			"priority": schema.Int64Attribute{
+				Computed: true,
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+			"propagation_status": schema.StringAttribute{
+				Description: "Whether CSC reports the record as served by the zone's nameservers, such as `PENDING` or `SYNCED`. " +
+					"Null when CSC does not report it.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *RecordDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RecordDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state RecordDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := RECORD_DATA_SOURCE_DEFAULT_TIMEOUT
+	if !state.Timeout.IsNull() {
+		var err error
+		timeout, err = time.ParseDuration(state.Timeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Timeout", fmt.Sprintf("Unable to parse timeout %q: %s", state.Timeout.ValueString(), err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if !state.UseCache.ValueBool() {
+		_, err := d.client.RefreshZone(ctx, state.Zone.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zone, got error: %s", err))
+			return
+		}
+	}
+
+	record, err := d.client.ReadRecord(ctx, state.Zone.ValueString(), state.Type.ValueString(), state.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read record, got error: %s", err))
+		return
+	}
+
+	state.Id = types.StringValue(record.Id)
+	state.Value = types.StringValue(record.Value)
+	state.Ttl = types.Int64Value(record.Ttl)
+	state.Priority = types.Int64Value(record.Priority)
+	state.Status = types.StringValue(record.Status)
+	state.PropagationStatus = types.StringNull()
+	if record.PropagationStatus != "" {
+		state.PropagationStatus = types.StringValue(record.PropagationStatus)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -2,12 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"terraform-provider-cscdm/internal/cscdm"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -19,9 +24,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &RecordResource{}
-	_ resource.ResourceWithConfigure   = &RecordResource{}
-	_ resource.ResourceWithImportState = &RecordResource{}
+	_ resource.Resource                   = &RecordResource{}
+	_ resource.ResourceWithConfigure      = &RecordResource{}
+	_ resource.ResourceWithImportState    = &RecordResource{}
+	_ resource.ResourceWithValidateConfig = &RecordResource{}
+	_ resource.ResourceWithModifyPlan     = &RecordResource{}
 )
 
 // NewRecordResource is a helper function to simplify the provider implementation.
@@ -41,9 +48,21 @@ type RecordResourceModel struct {
 	Key         types.String `tfsdk:"key"`
 	Value       types.String `tfsdk:"value"`
 	Ttl         types.Int64  `tfsdk:"ttl"`
+	InheritTtl  types.Bool   `tfsdk:"inherit_ttl"`
 	Priority    types.Int64  `tfsdk:"priority"`
+	Weight      types.Int64  `tfsdk:"weight"`
+	Port        types.Int32  `tfsdk:"port"`
 	Status      types.String `tfsdk:"status"`
 	LastUpdated types.String `tfsdk:"last_updated"`
+	SkipRefetch types.Bool   `tfsdk:"skip_refetch"`
+	ApiKey      types.String `tfsdk:"api_key"`
+	ApiToken    types.String `tfsdk:"api_token"`
+
+	PropagationStatus  types.String `tfsdk:"propagation_status"`
+	WaitForPropagation types.Bool   `tfsdk:"wait_for_propagation"`
+	AllowLastMxDelete  types.Bool   `tfsdk:"allow_last_mx_delete"`
+
+	Metadata map[string]types.String `tfsdk:"metadata"`
 }
 
 // Metadata returns the resource type name.
@@ -64,7 +83,7 @@ func (r *RecordResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 			"type": schema.StringAttribute{
 				Required: true,
 				Validators: []validator.String{
-					stringvalidator.OneOf("A", "AAAA", "CNAME", "MX", "NS", "TXT"),
+					stringvalidator.OneOf(cscdm.SupportedRecordTypes()...),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
@@ -74,23 +93,94 @@ func (r *RecordResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				Computed: true,
 			},
 			"key": schema.StringAttribute{
-				Required: true,
+				Description: "Record key. SRV keys take the form `_service._proto.name`. NS records may not be placed at the zone apex, whose nameservers are managed by CSC.",
+				Required:    true,
 			},
 			"value": schema.StringAttribute{
-				Required: true,
+				Description: "Record value. TLSA values take the form `<usage> <selector> <matching type> <certificate association data>`. CAA values take the form `[<flags>] <tag> \"<value>\"`, such as `issue \"letsencrypt.org\"`, where the tag is `issue`, `issuewild` or `iodef` and the flags, 0 when left out, are between 0 and 255.",
+				Required:    true,
 			},
 			"ttl": schema.Int64Attribute{
+				Description: "Record TTL in seconds. When unset the provider's `empty_ttl` decides what is sent; by default no TTL is sent and any TTL reported by CSC is tracked in state, " +
+					"so a server-assigned TTL shows up as drift. A TTL of 0 is sent as 0. Use `inherit_ttl` to follow the zone default instead.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.ConflictsWith(path.MatchRoot("inherit_ttl")),
+				},
+			},
+			"inherit_ttl": schema.BoolAttribute{
+				Description: "Explicitly inherit the zone default TTL. No TTL is sent and the TTL reported by CSC is ignored, " +
+					"so changes to the zone default never cause a diff. Conflicts with `ttl`.",
 				Optional: true,
 			},
 			"priority": schema.Int64Attribute{
 				Optional: true,
 			},
+			"weight": schema.Int64Attribute{
+				Description: "Relative weight among SRV records of the same priority. Only valid for SRV records, which fall back to the provider's zone default.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"port": schema.Int32Attribute{
+				Description: "Port the service is offered on. Required for SRV records and not valid for any other type.",
+				Optional:    true,
+				Validators: []validator.Int32{
+					int32validator.Between(0, 65535),
+				},
+			},
 			"status": schema.StringAttribute{
 				Computed: true,
 			},
 			"last_updated": schema.StringAttribute{
 				Computed: true,
 			},
+			"propagation_status": schema.StringAttribute{
+				Description: "Whether CSC reports the record as served by the zone's nameservers, such as `PENDING` or `SYNCED`. " +
+					"Null when CSC does not report it.",
+				Computed: true,
+			},
+			"wait_for_propagation": schema.BoolAttribute{
+				Description: "After creating or updating the record, keep re-reading it until CSC reports its `propagation_status` as `SYNCED`. " +
+					"Has no effect when CSC does not report a propagation status. Defaults to `false`.",
+				Optional: true,
+			},
+			"skip_refetch": schema.BoolAttribute{
+				Description: "After creating or updating the record, build its state from the configured values and look up only its id, " +
+					"instead of re-reading the zone. Faster for many single-record changes, but any normalization CSC applies " +
+					"to the submitted values is not seen until the next refresh. Defaults to `false`.",
+				Optional: true,
+			},
+			"metadata": schema.MapAttribute{
+				Description: "Free-form labels for the record, such as an owner or cost center, sent to CSC with the record and read back from it. Removing it clears the labels on the record. " +
+					"Ignored for record types CSC keeps no metadata on, in which case the configured labels are kept in state as they are.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"allow_last_mx_delete": schema.BoolAttribute{
+				Description: "Allow removing the last MX record at this record's key when the provider's `protect_last_mx` is set. " +
+					"Destroying the record uses the value in state, so it must be applied before the destroy. Defaults to `false`.",
+				Optional: true,
+			},
+			"api_key": schema.StringAttribute{
+				Description: "CSC Domain Manager API Key for the account owning this record's zone, overriding the provider's. " +
+					"Must be set together with `api_token`. An aliased provider configuration per account is an alternative.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("api_token")),
+				},
+			},
+			"api_token": schema.StringAttribute{
+				Description: "CSC Domain Manager API Token for the account owning this record's zone, overriding the provider's. " +
+					"Must be set together with `api_key`.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("api_key")),
+				},
+			},
 		},
 	}
 }
@@ -117,12 +207,334 @@ func (r *RecordResource) Configure(_ context.Context, req resource.ConfigureRequ
 	r.client = client
 }
 
+// ValidateConfig checks type-specific value formats and rejects apex NS
+// records at plan time.
+func (r *RecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RecordResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Zone.IsUnknown() && !config.Type.IsUnknown() && !config.Key.IsUnknown() {
+		if err := cscdm.CheckApexNs(config.Zone.ValueString(), config.Type.ValueString(), config.Key.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key"),
+				"Apex NS Record Not Allowed",
+				fmt.Sprintf("%s. Use the cscdm_zones data source to read them; NS records for subdomains can still be managed here.", err),
+			)
+		}
+	}
+
+	if !config.Type.IsUnknown() {
+		validateSrvAttributes(&config, resp)
+	}
+
+	if config.Type.IsUnknown() || config.Value.IsUnknown() || config.Value.IsNull() {
+		return
+	}
+
+	switch config.Type.ValueString() {
+	case "TLSA":
+		if _, err := cscdm.ParseTlsaValue(config.Value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("value"), "Invalid TLSA Record Value", err.Error())
+		}
+	case "CAA":
+		if _, err := cscdm.ParseCaaValue(config.Value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("value"), "Invalid CAA Record Value", err.Error())
+		}
+	}
+}
+
+// validateSrvAttributes requires a port on SRV records and rejects port and
+// weight on every other type, which CSC would ignore.
+func validateSrvAttributes(config *RecordResourceModel, resp *resource.ValidateConfigResponse) {
+	recordType := config.Type.ValueString()
+	if recordType == "SRV" {
+		if config.Port.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("port"), "Missing SRV Port", "SRV records must set port.")
+		}
+		return
+	}
+
+	onlySrv := func(name string, value attr.Value) {
+		if !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Attribute Only Valid for SRV Records",
+				fmt.Sprintf("%s is only valid for SRV records, not %s records.", name, recordType),
+			)
+		}
+	}
+	onlySrv("port", config.Port)
+	onlySrv("weight", config.Weight)
+}
+
+// ModifyPlan enforces the provider's min_ttl and record_hosting_types, which
+// are only known once the provider is configured, and checks the planned
+// change with CSC when validate_edits is set.
+func (r *RecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan RecordResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.checkHostingType(ctx, &plan, resp)
+	r.validateEdit(ctx, req, &plan, resp)
+
+	if r.client.MinTtl == 0 || plan.Ttl.IsNull() || plan.Ttl.IsUnknown() || plan.Ttl.ValueInt64() >= r.client.MinTtl {
+		return
+	}
+
+	if r.client.MinTtlAction == cscdm.MIN_TTL_ACTION_CLAMP {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("ttl"),
+			"TTL Raised to Provider Minimum",
+			fmt.Sprintf("ttl %d is below the provider's min_ttl of %d, so %d will be sent to CSC instead. State keeps the configured value.",
+				plan.Ttl.ValueInt64(), r.client.MinTtl, r.client.MinTtl),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("ttl"),
+		"TTL Below Provider Minimum",
+		fmt.Sprintf("ttl %d is below the provider's min_ttl of %d. Raise ttl, or set min_ttl_action to %q to have it raised automatically.",
+			plan.Ttl.ValueInt64(), r.client.MinTtl, cscdm.MIN_TTL_ACTION_CLAMP),
+	)
+}
+
+// checkHostingType warns about or rejects a record whose zone has a hosting
+// type outside the provider's record_hosting_types. Zones that cannot be
+// read are left for the apply to report.
+func (r *RecordResource) checkHostingType(ctx context.Context, plan *RecordResourceModel, resp *resource.ModifyPlanResponse) {
+	if plan.Zone.IsUnknown() {
+		return
+	}
+
+	err := r.clientFor(plan).CheckHostingType(ctx, plan.Zone.ValueString())
+	if !errors.Is(err, cscdm.ErrUnsupportedHostingType) {
+		return
+	}
+
+	if r.client.HostingTypeAction == cscdm.HOSTING_TYPE_ACTION_ERROR {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("zone"),
+			"Zone Hosting Type Does Not Support Records",
+			fmt.Sprintf("%s. Add the hosting type to the provider's record_hosting_types if CSC supports records in it.", err),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("zone"),
+		"Zone Hosting Type May Not Support Records",
+		fmt.Sprintf("%s. Edits to this record may fail. Add the hosting type to the provider's record_hosting_types if CSC supports records in it.", err),
+	)
+}
+
+// validateEdit checks the change a plan would make to a record without
+// applying it, when the provider's validate_edits is set. Plans with unknown
+// values, and records left unchanged, are not checked.
+func (r *RecordResource) validateEdit(ctx context.Context, req resource.ModifyPlanRequest, plan *RecordResourceModel, resp *resource.ModifyPlanResponse) {
+	if !r.client.ValidateEdits || plan.Zone.IsUnknown() || plan.Type.IsUnknown() || plan.Key.IsUnknown() || plan.Value.IsUnknown() || plan.Ttl.IsUnknown() || plan.Priority.IsUnknown() {
+		return
+	}
+
+	edit := cscdm.ZoneEdit{
+		Action:      "ADD",
+		RecordType:  plan.Type.ValueString(),
+		NewKey:      plan.Key.ValueString(),
+		NewValue:    plan.Value.ValueString(),
+		NewPriority: r.priorityFor(plan),
+		NewWeight:   r.weightFor(plan),
+		NewPort:     plan.Port.ValueInt32(),
+	}
+	edit.NewTtl, edit.ZeroTtl = r.ttlFor(plan)
+
+	if !req.State.Raw.IsNull() {
+		var state RecordResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.Key.Equal(plan.Key) && state.Value.Equal(plan.Value) && state.Ttl.Equal(plan.Ttl) && state.Priority.Equal(plan.Priority) {
+			return
+		}
+
+		edit.Action = "EDIT"
+		edit.CurrentKey = state.Key.ValueString()
+		edit.CurrentValue = state.Value.ValueString()
+	}
+
+	results, err := r.clientFor(plan).ValidateZoneEdits(ctx, cscdm.ZoneEditReq{ZoneName: plan.Zone.ValueString(), Edits: []cscdm.ZoneEdit{edit}})
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to Validate Record Change",
+			fmt.Sprintf("The planned change could not be checked at edit_validate_path and will be checked when applied: %s", err),
+		)
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value"),
+				"Invalid Record Change",
+				fmt.Sprintf("CSC would reject this change to %s record '%s' in zone %s: %s",
+					plan.Type.ValueString(), plan.Key.ValueString(), plan.Zone.ValueString(), result.Err),
+			)
+		}
+	}
+}
+
+// clientFor returns the client to use for a record, honoring any
+// per-resource credential override.
+func (r *RecordResource) clientFor(model *RecordResourceModel) *cscdm.Client {
+	if model.ApiKey.IsNull() || model.ApiToken.IsNull() {
+		return r.client
+	}
+
+	return r.client.WithCredentials(model.ApiKey.ValueString(), model.ApiToken.ValueString())
+}
+
+// ttlFor returns the TTL to send for a record and whether it is an explicit
+// zero: a ttl of 0, or an unset ttl under the provider's empty_ttl of
+// "zero". inherit_ttl always leaves the TTL to CSC.
+func (r *RecordResource) ttlFor(model *RecordResourceModel) (int64, bool) {
+	client := r.clientFor(model)
+	if model.Ttl.IsNull() {
+		return 0, !model.InheritTtl.ValueBool() && client.EmptyTtl == cscdm.EMPTY_TTL_ZERO
+	}
+
+	ttl := model.Ttl.ValueInt64()
+	if ttl == 0 && client.MinTtl > 0 && client.MinTtlAction == cscdm.MIN_TTL_ACTION_CLAMP {
+		return client.MinTtl, false
+	}
+
+	return client.ClampTtl(ttl), ttl == 0
+}
+
+// keepClampedTtl restores a configured TTL that was raised to min_ttl before
+// being sent, so the clamped value reported by CSC is not seen as drift.
+func (r *RecordResource) keepClampedTtl(dst *RecordResourceModel, configured types.Int64) {
+	if configured.IsNull() || configured.IsUnknown() {
+		return
+	}
+
+	sent := *dst
+	sent.Ttl = configured
+	clamped, _ := r.ttlFor(&sent)
+	if clamped != configured.ValueInt64() && dst.Ttl.ValueInt64() == clamped {
+		dst.Ttl = configured
+	}
+}
+
+// keepZeroTtl restores the configured TTL when a zero TTL was sent and CSC
+// reports it as zero, or raised to the zone's SOA minimum as CSC may do, so
+// neither is seen as drift.
+func (r *RecordResource) keepZeroTtl(dst *RecordResourceModel, configured types.Int64) {
+	if configured.IsUnknown() {
+		return
+	}
+
+	sent := *dst
+	sent.Ttl = configured
+	if _, zero := r.ttlFor(&sent); !zero {
+		return
+	}
+
+	if dst.Ttl.IsNull() || dst.Ttl.ValueInt64() == 0 {
+		dst.Ttl = configured
+		return
+	}
+
+	zone, err := r.clientFor(dst).GetZone(dst.Zone.ValueString())
+	if err == nil && dst.Ttl.ValueInt64() == zone.SOA.TtlMin {
+		dst.Ttl = configured
+	}
+}
+
+// priorityFor returns the priority to send for a record, falling back to the
+// zone default when the record sets none.
+func (r *RecordResource) priorityFor(model *RecordResourceModel) int64 {
+	if !model.Priority.IsNull() {
+		return model.Priority.ValueInt64()
+	}
+
+	return r.clientFor(model).DefaultPriority(model.Zone.ValueString(), model.Type.ValueString())
+}
+
+// keepDefaultPriority leaves priority unset in state when it was omitted from
+// the configuration and CSC reports the zone default that was sent instead.
+func (r *RecordResource) keepDefaultPriority(dst *RecordResourceModel, configured types.Int64) {
+	if !configured.IsNull() {
+		return
+	}
+
+	if dst.Priority.ValueInt64() == r.clientFor(dst).DefaultPriority(dst.Zone.ValueString(), dst.Type.ValueString()) {
+		dst.Priority = types.Int64Null()
+	}
+}
+
+// weightFor returns the weight to send for a record, falling back to the
+// zone default when the record sets none.
+func (r *RecordResource) weightFor(model *RecordResourceModel) int64 {
+	if !model.Weight.IsNull() {
+		return model.Weight.ValueInt64()
+	}
+
+	return r.clientFor(model).DefaultWeight(model.Zone.ValueString(), model.Type.ValueString())
+}
+
+// keepDefaultWeight leaves weight unset in state when it was omitted from the
+// configuration and CSC reports the zone default that was sent instead.
+func (r *RecordResource) keepDefaultWeight(dst *RecordResourceModel, configured types.Int64) {
+	if !configured.IsNull() {
+		return
+	}
+
+	if dst.Weight.ValueInt64() == r.clientFor(dst).DefaultWeight(dst.Zone.ValueString(), dst.Type.ValueString()) {
+		dst.Weight = types.Int64Null()
+	}
+}
+
+// metadataFor returns the metadata to send for a record, nil when none is
+// configured. When prior, the record's state before the edit, has metadata
+// that is no longer configured, an empty map is returned so CSC clears it
+// rather than keeping the old labels.
+func metadataFor(model *RecordResourceModel, prior *RecordResourceModel) map[string]string {
+	if len(model.Metadata) == 0 {
+		if prior != nil && len(prior.Metadata) > 0 {
+			return map[string]string{}
+		}
+		return nil
+	}
+
+	metadata := make(map[string]string, len(model.Metadata))
+	for name, value := range model.Metadata {
+		metadata[name] = value.ValueString()
+	}
+
+	return metadata
+}
+
 func copyRecord(dst *RecordResourceModel, src *cscdm.ZoneRecord) {
 	dst.Id = types.StringValue(src.Id)
 	dst.Key = types.StringValue(src.Key)
-	dst.Value = types.StringValue(src.Value)
+	// Keep the configured form of a value CSC stored in an equivalent one,
+	// such as a compressed IPv6 address, so it does not show as a diff.
+	if dst.Value.IsNull() || dst.Value.IsUnknown() || !cscdm.RecordValuesEqual(dst.Type.ValueString(), dst.Value.ValueString(), src.Value) {
+		dst.Value = types.StringValue(src.Value)
+	}
 
-	if src.Ttl == 0 {
+	if src.Ttl == 0 || dst.InheritTtl.ValueBool() {
 		dst.Ttl = types.Int64Null()
 	} else {
 		dst.Ttl = types.Int64Value(src.Ttl)
@@ -134,7 +546,37 @@ func copyRecord(dst *RecordResourceModel, src *cscdm.ZoneRecord) {
 		dst.Priority = types.Int64Value(src.Priority)
 	}
 
+	if dst.Type.ValueString() == "SRV" {
+		dst.Weight = types.Int64Value(src.Weight)
+		dst.Port = types.Int32Value(src.Port)
+	}
+
 	dst.Status = types.StringValue(src.Status)
+
+	// CSC reports no metadata for record types it keeps none on, so the
+	// configured metadata is kept rather than shown as removed.
+	if len(src.Metadata) > 0 {
+		dst.Metadata = make(map[string]types.String, len(src.Metadata))
+		for name, value := range src.Metadata {
+			dst.Metadata[name] = types.StringValue(value)
+		}
+	}
+
+	if src.PropagationStatus == "" {
+		dst.PropagationStatus = types.StringNull()
+	} else {
+		dst.PropagationStatus = types.StringValue(src.PropagationStatus)
+	}
+}
+
+// awaitPropagation waits for a record just written to propagate when the
+// configuration asks for it, returning the record as last read.
+func (r *RecordResource) awaitPropagation(ctx context.Context, model *RecordResourceModel, record *cscdm.ZoneRecord) (*cscdm.ZoneRecord, error) {
+	if !model.WaitForPropagation.ValueBool() {
+		return record, nil
+	}
+
+	return r.clientFor(model).WaitForPropagation(ctx, model.Zone.ValueString(), model.Type.ValueString(), record.Id)
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -153,19 +595,36 @@ func (r *RecordResource) Create(ctx context.Context, req resource.CreateRequest,
 			RecordType:  plan.Type.ValueString(),
 			NewKey:      plan.Key.ValueString(),
 			NewValue:    plan.Value.ValueString(),
-			NewTtl:      plan.Ttl.ValueInt64(),
-			NewPriority: plan.Priority.ValueInt64(),
+			NewPriority: r.priorityFor(&plan),
+			NewWeight:   r.weightFor(&plan),
+			NewPort:     plan.Port.ValueInt32(),
+			NewMetadata: metadataFor(&plan, nil),
+			SkipRefetch: plan.SkipRefetch.ValueBool(),
 		},
 		ZoneName: plan.Zone.ValueString(),
 	}
+	recordAction.NewTtl, recordAction.ZeroTtl = r.ttlFor(&plan)
 
-	zoneRecord, err := r.client.PerformRecordAction(&recordAction)
+	zoneRecord, err := r.clientFor(&plan).PerformRecordAction(ctx, &recordAction)
 	if err != nil {
 		resp.Diagnostics.AddError("error creating record", err.Error())
 		return
 	}
 
+	// The record was written either way, so keep it in state when the wait
+	// fails rather than losing track of it.
+	if synced, err := r.awaitPropagation(ctx, &plan, zoneRecord); err != nil {
+		resp.Diagnostics.AddError("error waiting for record to propagate", err.Error())
+	} else {
+		zoneRecord = synced
+	}
+
+	configuredTtl, configuredPriority, configuredWeight := plan.Ttl, plan.Priority, plan.Weight
 	copyRecord(&plan, zoneRecord)
+	r.keepClampedTtl(&plan, configuredTtl)
+	r.keepZeroTtl(&plan, configuredTtl)
+	r.keepDefaultPriority(&plan, configuredPriority)
+	r.keepDefaultWeight(&plan, configuredWeight)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	// Set state to fully populated data
@@ -183,19 +642,23 @@ func (r *RecordResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	zone, err := r.client.GetZone(state.Zone.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("error fetching zone", err.Error())
+	record, err := r.clientFor(&state).ReadRecordById(ctx, state.Zone.ValueString(), state.Type.ValueString(), state.Id.ValueString())
+	// A record or zone deleted outside Terraform is planned for recreation.
+	if errors.Is(err, cscdm.ErrRecordNotFound) || errors.Is(err, cscdm.ErrZoneNotFound) {
+		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	record, err := r.client.GetRecordByTypeById(zone, state.Type.ValueString(), state.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("error getting record from zone", err.Error())
 		return
 	}
 
+	configuredTtl, configuredPriority, configuredWeight := state.Ttl, state.Priority, state.Weight
 	copyRecord(&state, record)
+	r.keepClampedTtl(&state, configuredTtl)
+	r.keepZeroTtl(&state, configuredTtl)
+	r.keepDefaultPriority(&state, configuredPriority)
+	r.keepDefaultWeight(&state, configuredWeight)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -222,25 +685,43 @@ func (r *RecordResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	recordAction := cscdm.RecordAction{
 		ZoneEdit: cscdm.ZoneEdit{
-			Action:       "EDIT",
-			RecordType:   state.Type.ValueString(),
-			CurrentKey:   state.Key.ValueString(),
-			CurrentValue: state.Value.ValueString(),
-			NewKey:       plan.Key.ValueString(),
-			NewValue:     plan.Value.ValueString(),
-			NewTtl:       plan.Ttl.ValueInt64(),
-			NewPriority:  plan.Priority.ValueInt64(),
+			Action:          "EDIT",
+			RecordType:      state.Type.ValueString(),
+			CurrentKey:      state.Key.ValueString(),
+			CurrentValue:    state.Value.ValueString(),
+			NewKey:          plan.Key.ValueString(),
+			NewValue:        plan.Value.ValueString(),
+			NewPriority:     r.priorityFor(&plan),
+			NewWeight:       r.weightFor(&plan),
+			NewPort:         plan.Port.ValueInt32(),
+			NewMetadata:     metadataFor(&plan, &state),
+			SkipRefetch:     plan.SkipRefetch.ValueBool(),
+			AllowLastRecord: plan.AllowLastMxDelete.ValueBool(),
 		},
 		ZoneName: plan.Zone.ValueString(),
 	}
+	recordAction.NewTtl, recordAction.ZeroTtl = r.ttlFor(&plan)
 
-	zoneRecord, err := r.client.PerformRecordAction(&recordAction)
+	zoneRecord, err := r.clientFor(&plan).PerformRecordEdit(ctx, &recordAction)
 	if err != nil {
 		resp.Diagnostics.AddError("error updating record", err.Error())
 		return
 	}
 
+	// The record was written either way, so keep it in state when the wait
+	// fails rather than losing track of it.
+	if synced, err := r.awaitPropagation(ctx, &plan, zoneRecord); err != nil {
+		resp.Diagnostics.AddError("error waiting for record to propagate", err.Error())
+	} else {
+		zoneRecord = synced
+	}
+
+	configuredTtl, configuredPriority, configuredWeight := plan.Ttl, plan.Priority, plan.Weight
 	copyRecord(&plan, zoneRecord)
+	r.keepClampedTtl(&plan, configuredTtl)
+	r.keepZeroTtl(&plan, configuredTtl)
+	r.keepDefaultPriority(&plan, configuredPriority)
+	r.keepDefaultWeight(&plan, configuredWeight)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	// Set state to fully populated data
@@ -260,33 +741,72 @@ func (r *RecordResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	recordAction := cscdm.RecordAction{
 		ZoneEdit: cscdm.ZoneEdit{
-			Action:       "PURGE",
-			RecordType:   state.Type.ValueString(),
-			CurrentKey:   state.Key.ValueString(),
-			CurrentValue: state.Value.ValueString(),
+			Action:          "PURGE",
+			RecordType:      state.Type.ValueString(),
+			CurrentKey:      state.Key.ValueString(),
+			CurrentValue:    state.Value.ValueString(),
+			AllowLastRecord: state.AllowLastMxDelete.ValueBool(),
 		},
 		ZoneName: state.Zone.ValueString(),
 	}
 
-	_, err := r.client.PerformRecordAction(&recordAction)
+	orphans, err := r.clientFor(&state).PerformRecordPurge(ctx, &recordAction)
 	if err != nil {
 		resp.Diagnostics.AddError("error updating record", err.Error())
 		return
 	}
+
+	if len(orphans) > 0 {
+		resp.Diagnostics.AddWarning(
+			"Deleted record is still referenced",
+			fmt.Sprintf(
+				"Deleting %s record '%s' left the following records in zone %s pointing at a name that no longer resolves:\n  - %s",
+				state.Type.ValueString(), state.Key.ValueString(), state.Zone.ValueString(), strings.Join(orphans, "\n  - "),
+			),
+		)
+	}
 }
 
+// ImportState accepts either `zone:type:id` or `zone:type:key:value` and
+// resolves the record up front so the imported state is fully populated.
 func (r *RecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	idParts := strings.Split(req.ID, ":")
+	// The value is last so that values containing colons, such as IPv6
+	// addresses, survive the split.
+	idParts := strings.SplitN(req.ID, ":", 4)
 
-	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+	if len(idParts) < 3 || slices.Contains(idParts, "") {
 		resp.Diagnostics.AddError(
 			"unexpected import identifier",
-			fmt.Sprintf("expected import identifier with format: `zone:type:id`, got: %q", req.ID),
+			fmt.Sprintf("expected import identifier with format: `zone:type:id` or `zone:type:key:value`, got: %q", req.ID),
 		)
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("zone"), idParts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), idParts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
+	zone, err := r.client.GetZone(idParts[0])
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching zone", err.Error())
+		return
+	}
+
+	var record *cscdm.ZoneRecord
+	if len(idParts) == 3 {
+		record, err = r.client.GetRecordByTypeById(zone, idParts[1], idParts[2])
+	} else {
+		record, err = r.client.GetRecordByTypeByKeyValue(zone, idParts[1], idParts[2], idParts[3])
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error getting record from zone", err.Error())
+		return
+	}
+
+	state := RecordResourceModel{
+		Zone:        types.StringValue(idParts[0]),
+		Type:        types.StringValue(idParts[1]),
+		InheritTtl:  types.BoolNull(),
+		LastUpdated: types.StringNull(),
+		SkipRefetch: types.BoolNull(),
+	}
+	copyRecord(&state, record)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -0,0 +1,515 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newTestClient returns a client backed by a server that serves the given
+// zones read-only.
+func newTestClient(t *testing.T, zones ...cscdm.Zone) *cscdm.Client {
+	t.Helper()
+
+	byName := make(map[string]cscdm.Zone)
+	for _, zone := range zones {
+		byName[zone.ZoneName] = zone
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zone, ok := byName[strings.TrimPrefix(r.URL.Path, "/zones/")]
+		if r.Method != http.MethodGet || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(cscdm.ZoneEditErr{Code: "NOT_FOUND"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(zone)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	return client
+}
+
+func newTestRecordResource(t *testing.T, client *cscdm.Client) (*provider.RecordResource, resource.SchemaResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	r, ok := provider.NewRecordResource().(*provider.RecordResource)
+	if !ok {
+		t.Fatal("NewRecordResource did not return a *RecordResource")
+	}
+
+	var configureResp resource.ConfigureResponse
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: client}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure failed: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	return r, schemaResp
+}
+
+func importRecord(t *testing.T, client *cscdm.Client, id string) (tfsdk.State, bool) {
+	t.Helper()
+
+	ctx := context.Background()
+	r, schemaResp := newTestRecordResource(t, client)
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+
+	return resp.State, !resp.Diagnostics.HasError()
+}
+
+func TestRecordResource_ImportState(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		A: []cscdm.ZoneRecord{
+			{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300},
+			{Id: "102", Key: "www", Value: "10.0.0.2", Ttl: 600},
+		},
+		AAAA: []cscdm.ZoneRecord{
+			{Id: "201", Key: "www", Value: "2001:db8::1"},
+		},
+	})
+
+	tests := []struct {
+		id    string
+		zone  string
+		rtype string
+		rid   string
+		key   string
+		value string
+	}{
+		{"example.com:A:102", "example.com", "A", "102", "www", "10.0.0.2"},
+		{"example.com:A:www:10.0.0.1", "example.com", "A", "101", "www", "10.0.0.1"},
+		{"example.com:AAAA:www:2001:db8::1", "example.com", "AAAA", "201", "www", "2001:db8::1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			state, ok := importRecord(t, client, test.id)
+			if !ok {
+				t.Fatalf("Import of %q failed", test.id)
+			}
+
+			expected := map[string]string{
+				"zone":  test.zone,
+				"type":  test.rtype,
+				"id":    test.rid,
+				"key":   test.key,
+				"value": test.value,
+			}
+			for attr, want := range expected {
+				var got types.String
+				state.GetAttribute(context.Background(), path.Root(attr), &got)
+				if got.ValueString() != want {
+					t.Errorf("Expected %s %q, got %q", attr, want, got.ValueString())
+				}
+			}
+		})
+	}
+}
+
+func TestRecordResource_ImportStateRejectsBadIdentifiers(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{ZoneName: "example.com"})
+
+	for _, id := range []string{"example.com", "example.com:A", "example.com::1", "example.com:A:999"} {
+		if _, ok := importRecord(t, client, id); ok {
+			t.Errorf("Expected import of %q to fail", id)
+		}
+	}
+}
+
+func planRecordTtl(t *testing.T, client *cscdm.Client, ttl int64) diag.Diagnostics {
+	t.Helper()
+
+	ctx := context.Background()
+	r, schemaResp := newTestRecordResource(t, client)
+
+	plan := tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := plan.Set(ctx, &provider.RecordResourceModel{
+		Zone:        types.StringValue("example.com"),
+		Type:        types.StringValue("A"),
+		Id:          types.StringUnknown(),
+		Key:         types.StringValue("www"),
+		Value:       types.StringValue("10.0.0.1"),
+		Ttl:         types.Int64Value(ttl),
+		InheritTtl:  types.BoolNull(),
+		Priority:    types.Int64Null(),
+		Status:      types.StringUnknown(),
+		LastUpdated: types.StringUnknown(),
+		SkipRefetch: types.BoolNull(),
+		ApiKey:      types.StringNull(),
+		ApiToken:    types.StringNull(),
+
+		PropagationStatus:  types.StringUnknown(),
+		WaitForPropagation: types.BoolNull(),
+		AllowLastMxDelete:  types.BoolNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("Failed to build plan: %v", diags)
+	}
+
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan}, &resp)
+
+	return resp.Diagnostics
+}
+
+func TestRecordResource_ModifyPlanEnforcesMinTtl(t *testing.T) {
+	client := newTestClient(t)
+	client.MinTtl = 300
+
+	if diags := planRecordTtl(t, client, 60); !diags.HasError() {
+		t.Errorf("Expected a TTL below min_ttl to fail the plan")
+	}
+	if diags := planRecordTtl(t, client, 300); len(diags) != 0 {
+		t.Errorf("Expected a TTL at min_ttl to plan cleanly, got %v", diags)
+	}
+
+	client.MinTtlAction = cscdm.MIN_TTL_ACTION_CLAMP
+
+	diags := planRecordTtl(t, client, 60)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("Expected a single clamp warning, got %v", diags)
+	}
+	if ttl := client.ClampTtl(60); ttl != 300 {
+		t.Errorf("Expected TTL to be clamped to 300, got %d", ttl)
+	}
+}
+
+func TestRecordResource_ModifyPlanChecksHostingType(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{ZoneName: "example.com", HostingType: "BASIC"})
+
+	if diags := planRecordTtl(t, client, 300); len(diags) != 0 {
+		t.Errorf("Expected hosting types to go unchecked by default, got %v", diags)
+	}
+
+	client.RecordHostingTypes = []string{"basic"}
+	if diags := planRecordTtl(t, client, 300); len(diags) != 0 {
+		t.Errorf("Expected an allowed hosting type to plan cleanly, got %v", diags)
+	}
+
+	client.RecordHostingTypes = []string{"ADVANCED"}
+	if diags := planRecordTtl(t, client, 300); diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("Expected a single hosting type warning, got %v", diags)
+	}
+
+	client.HostingTypeAction = cscdm.HOSTING_TYPE_ACTION_ERROR
+	if diags := planRecordTtl(t, client, 300); !diags.HasError() {
+		t.Errorf("Expected an unsupported hosting type to fail the plan")
+	}
+}
+
+func TestClient_DefaultPriorityPrecedence(t *testing.T) {
+	client := newTestClient(t)
+	client.ZoneDefaults = map[string]cscdm.ZoneDefaults{"Example.com.": {Priority: 10, Weight: 5}}
+
+	if p := client.DefaultPriority("example.com", "MX"); p != 10 {
+		t.Errorf("Expected MX records to default to the zone priority 10, got %d", p)
+	}
+	if p := client.DefaultPriority("example.com", "A"); p != 0 {
+		t.Errorf("Expected A records to have no default priority, got %d", p)
+	}
+	if p := client.DefaultPriority("other.com", "MX"); p != 0 {
+		t.Errorf("Expected zones without defaults to fall back to no priority, got %d", p)
+	}
+}
+
+// readRecord reads the A record with id 101 at key in example.com.
+func readRecord(t *testing.T, client *cscdm.Client, key string) resource.ReadResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	r, schemaResp := newTestRecordResource(t, client)
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, &provider.RecordResourceModel{
+		Zone:        types.StringValue("example.com"),
+		Type:        types.StringValue("A"),
+		Id:          types.StringValue("101"),
+		Key:         types.StringValue(key),
+		Value:       types.StringValue("10.0.0.1"),
+		Ttl:         types.Int64Null(),
+		InheritTtl:  types.BoolNull(),
+		Priority:    types.Int64Null(),
+		Status:      types.StringNull(),
+		LastUpdated: types.StringNull(),
+		SkipRefetch: types.BoolNull(),
+		ApiKey:      types.StringNull(),
+		ApiToken:    types.StringNull(),
+
+		PropagationStatus:  types.StringNull(),
+		WaitForPropagation: types.BoolNull(),
+		AllowLastMxDelete:  types.BoolNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("Failed to build state: %v", diags)
+	}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+
+	return resp
+}
+
+func readRecordTtl(t *testing.T, client *cscdm.Client, key string) types.Int64 {
+	t.Helper()
+
+	resp := readRecord(t, client, key)
+
+	var ttl types.Int64
+	resp.State.GetAttribute(context.Background(), path.Root("ttl"), &ttl)
+	return ttl
+}
+
+func TestRecordResource_ReadRemovesMissingRecord(t *testing.T) {
+	for name, client := range map[string]*cscdm.Client{
+		"record deleted": newTestClient(t, cscdm.Zone{
+			ZoneName: "example.com",
+			A:        []cscdm.ZoneRecord{{Id: "102", Key: "mail", Value: "10.0.0.2"}},
+		}),
+		"type list null": newTestClient(t, cscdm.Zone{ZoneName: "example.com"}),
+		"zone deleted":   newTestClient(t),
+	} {
+		resp := readRecord(t, client, "www")
+		if !resp.State.Raw.IsNull() {
+			t.Errorf("%s: expected the record to be removed from state", name)
+		}
+	}
+}
+
+func TestRecordResource_ReadEmptyTtl(t *testing.T) {
+	for _, key := range []string{"www", "@"} {
+		t.Run(key, func(t *testing.T) {
+			for _, test := range []struct {
+				emptyTtl string
+				reported int64
+				tracked  bool
+			}{
+				{cscdm.EMPTY_TTL_SERVER_DEFAULT, 3600, true},
+				{cscdm.EMPTY_TTL_SERVER_DEFAULT, 300, true},
+				{cscdm.EMPTY_TTL_ZERO, 0, false},
+				{cscdm.EMPTY_TTL_ZERO, 300, false},
+				{cscdm.EMPTY_TTL_ZERO, 3600, true},
+			} {
+				client := newTestClient(t, cscdm.Zone{
+					ZoneName: "example.com",
+					SOA:      cscdm.ZoneSoaRecord{TtlMin: 300},
+					A:        []cscdm.ZoneRecord{{Id: "101", Key: key, Value: "10.0.0.1", Ttl: test.reported}},
+				})
+				client.EmptyTtl = test.emptyTtl
+
+				ttl := readRecordTtl(t, client, key)
+				if test.tracked && ttl.ValueInt64() != test.reported {
+					t.Errorf("%s: expected reported TTL %d to be tracked, got %s", test.emptyTtl, test.reported, ttl)
+				}
+				if !test.tracked && !ttl.IsNull() {
+					t.Errorf("%s: expected reported TTL %d to be ignored, got %s", test.emptyTtl, test.reported, ttl)
+				}
+			}
+		})
+	}
+}
+
+func validateRecordConfig(t *testing.T, model *provider.RecordResourceModel) diag.Diagnostics {
+	t.Helper()
+
+	ctx := context.Background()
+	r, schemaResp := newTestRecordResource(t, newTestClient(t))
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+
+	var resp resource.ValidateConfigResponse
+	r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config(state)}, &resp)
+
+	return resp.Diagnostics
+}
+
+func TestRecordResource_ValidateConfigSrvAttributes(t *testing.T) {
+	srv := &provider.RecordResourceModel{
+		Zone:   types.StringValue("example.com"),
+		Type:   types.StringValue("SRV"),
+		Key:    types.StringValue("_sip._tcp"),
+		Value:  types.StringValue("sip.example.com"),
+		Weight: types.Int64Value(5),
+		Port:   types.Int32Value(5060),
+	}
+	if diags := validateRecordConfig(t, srv); diags.HasError() {
+		t.Errorf("Expected an SRV record with a port to be valid, got %v", diags)
+	}
+
+	srv.Port = types.Int32Null()
+	if diags := validateRecordConfig(t, srv); !diags.HasError() {
+		t.Errorf("Expected an SRV record without a port to be rejected")
+	}
+
+	a := &provider.RecordResourceModel{
+		Zone:  types.StringValue("example.com"),
+		Type:  types.StringValue("A"),
+		Key:   types.StringValue("www"),
+		Value: types.StringValue("10.0.0.1"),
+		Port:  types.Int32Value(80),
+	}
+	if diags := validateRecordConfig(t, a); diags.ErrorsCount() != 1 {
+		t.Errorf("Expected port on an A record to be rejected, got %v", diags)
+	}
+}
+
+// newEditingTestClient returns a client backed by a server that serves zone
+// and applies EDITs of its A records to it. Like CSC, an EDIT that sends no
+// metadata leaves a record's metadata as it was.
+func newEditingTestClient(t *testing.T, zone *cscdm.Zone) *cscdm.Client {
+	t.Helper()
+
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch path := strings.TrimPrefix(r.URL.Path, "/"); {
+		case r.Method == http.MethodPost && path == "zones/edits":
+			var req cscdm.ZoneEditReq
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			for _, edit := range req.Edits {
+				for i, record := range zone.A {
+					if edit.Action != "EDIT" || record.Key != edit.CurrentKey || record.Value != edit.CurrentValue {
+						continue
+					}
+					zone.A[i].Key, zone.A[i].Value = edit.NewKey, edit.NewValue
+					if edit.NewMetadata != nil {
+						zone.A[i].Metadata = edit.NewMetadata
+					}
+				}
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"links": map[string]string{"status": "zones/edits/status/1"}})
+		case r.Method == http.MethodGet && path == "zones/edits/status/1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"content": map[string]string{"status": "COMPLETED"}})
+		case r.Method == http.MethodGet && path == "zones/"+zone.ZoneName:
+			_ = json.NewEncoder(w).Encode(zone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(cscdm.ZoneEditErr{Code: "NOT_FOUND"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/", PollInterval: 10 * time.Millisecond, Synchronous: true}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	return client
+}
+
+func TestRecordResource_RemovingMetadataClearsIt(t *testing.T) {
+	client := newEditingTestClient(t, &cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "101", Key: "www", Value: "10.0.0.1"}},
+	})
+
+	ctx := context.Background()
+	r, schemaResp := newTestRecordResource(t, client)
+	empty := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	model := func(metadata map[string]types.String) *provider.RecordResourceModel {
+		return &provider.RecordResourceModel{
+			Zone:     types.StringValue("example.com"),
+			Type:     types.StringValue("A"),
+			Id:       types.StringValue("101"),
+			Key:      types.StringValue("www"),
+			Value:    types.StringValue("10.0.0.1"),
+			Metadata: metadata,
+		}
+	}
+	update := func(state tfsdk.State, planned *provider.RecordResourceModel) tfsdk.State {
+		t.Helper()
+
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: empty}
+		if diags := plan.Set(ctx, planned); diags.HasError() {
+			t.Fatalf("Failed to build plan: %v", diags)
+		}
+
+		resp := resource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: empty}}
+		r.Update(ctx, resource.UpdateRequest{Plan: plan, State: state}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Update failed: %v", resp.Diagnostics)
+		}
+
+		return resp.State
+	}
+	metadataIn := func(state tfsdk.State) map[string]types.String {
+		t.Helper()
+
+		var metadata map[string]types.String
+		if diags := state.GetAttribute(ctx, path.Root("metadata"), &metadata); diags.HasError() {
+			t.Fatalf("Failed to read metadata: %v", diags)
+		}
+		return metadata
+	}
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: empty}
+	if diags := state.Set(ctx, model(nil)); diags.HasError() {
+		t.Fatalf("Failed to build state: %v", diags)
+	}
+
+	state = update(state, model(map[string]types.String{"owner": types.StringValue("platform")}))
+	if metadata := metadataIn(state); metadata["owner"].ValueString() != "platform" {
+		t.Fatalf("Expected the metadata to be set, got %v", metadata)
+	}
+
+	state = update(state, model(nil))
+
+	// Refresh as the next plan would: with the metadata gone from the
+	// configuration, it must be gone from the record too, or the plan shows
+	// a diff that never goes away.
+	readResp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", readResp.Diagnostics)
+	}
+	if metadata := metadataIn(readResp.State); len(metadata) != 0 {
+		t.Errorf("Expected the removed metadata to stay removed, got %v", metadata)
+	}
+}
@@ -0,0 +1,341 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-cscdm/internal/cscdm"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &SoaResource{}
+	_ resource.ResourceWithConfigure      = &SoaResource{}
+	_ resource.ResourceWithImportState    = &SoaResource{}
+	_ resource.ResourceWithModifyPlan     = &SoaResource{}
+	_ resource.ResourceWithValidateConfig = &SoaResource{}
+)
+
+// NewSoaResource is a helper function to simplify the provider implementation.
+func NewSoaResource() resource.Resource {
+	return &SoaResource{}
+}
+
+// SoaResource is the resource implementation.
+type SoaResource struct {
+	client *cscdm.Client
+}
+
+type SoaResourceModel struct {
+	Id        types.String `tfsdk:"id"`
+	ZoneName  types.String `tfsdk:"zone_name"`
+	Refresh   types.Int64  `tfsdk:"refresh"`
+	Retry     types.Int64  `tfsdk:"retry"`
+	Expire    types.Int64  `tfsdk:"expire"`
+	TtlMin    types.Int64  `tfsdk:"ttl_min"`
+	TechEmail types.String `tfsdk:"tech_email"`
+	Serial    types.Int64  `tfsdk:"serial"`
+}
+
+// Metadata returns the resource type name.
+func (r *SoaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_soa"
+}
+
+// Schema defines the schema for the resource.
+func (r *SoaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	timer := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			Description: description + " Left as CSC has it when unset.",
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "The SOA record of an existing zone. Each zone has exactly one SOA, so at most one cscdm_soa may target a zone. " +
+			"Creating the resource adopts the SOA and applies the configured fields; destroying it only removes it from state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"refresh": timer("Seconds between secondary nameserver refreshes."),
+			"retry":   timer("Seconds a secondary nameserver waits before retrying a failed refresh."),
+			"expire":  timer("Seconds after which a secondary nameserver that cannot refresh stops answering."),
+			"ttl_min": timer("Minimum TTL, in seconds, used for negative caching."),
+			"tech_email": schema.StringAttribute{
+				Description: "Responsible person, as a mailbox such as `hostmaster@example.com` or in DNS notation such as `hostmaster.example.com`. " +
+					"Left as CSC has it when unset.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"serial": schema.Int64Attribute{
+				Description: "Zone serial, which CSC increments on every change to the zone.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *SoaResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ValidateConfig checks that tech_email can be read as a mailbox.
+func (r *SoaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SoaResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.TechEmail.IsNull() || config.TechEmail.IsUnknown() {
+		return
+	}
+
+	if _, err := cscdm.MailboxToSoaEmail(config.TechEmail.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("tech_email"), "Invalid SOA Tech Email", err.Error())
+	}
+}
+
+// soaEditFor returns the edit that applies the configured SOA fields.
+func soaEditFor(model *SoaResourceModel) cscdm.ZoneSoaEdit {
+	configured := func(value types.Int64) *int64 {
+		if value.IsNull() || value.IsUnknown() {
+			return nil
+		}
+		v := value.ValueInt64()
+		return &v
+	}
+
+	edit := cscdm.ZoneSoaEdit{
+		Refresh: configured(model.Refresh),
+		Retry:   configured(model.Retry),
+		Expire:  configured(model.Expire),
+		TtlMin:  configured(model.TtlMin),
+	}
+	if !model.TechEmail.IsNull() && !model.TechEmail.IsUnknown() {
+		edit.TechEmail = model.TechEmail.ValueString()
+	}
+
+	return edit
+}
+
+func copySoa(dst *SoaResourceModel, src *cscdm.ZoneSoaRecord) {
+	dst.Id = dst.ZoneName
+	dst.Refresh = types.Int64Value(src.Refresh)
+	dst.Retry = types.Int64Value(src.Retry)
+	dst.Expire = types.Int64Value(src.Expire)
+	dst.TtlMin = types.Int64Value(src.TtlMin)
+	dst.Serial = types.Int64Value(src.Serial)
+
+	// Keep the configured notation of an equivalent tech email.
+	if dst.TechEmail.IsNull() || dst.TechEmail.IsUnknown() || !cscdm.SoaEmailsEqual(dst.TechEmail.ValueString(), src.TechEmail) {
+		dst.TechEmail = types.StringValue(src.TechEmail)
+	}
+}
+
+// apply pushes the configured SOA fields to CSC, skipping the edit when none
+// are set, and copies the resulting SOA into the model.
+func (r *SoaResource) apply(ctx context.Context, model *SoaResourceModel) error {
+	zoneName := model.ZoneName.ValueString()
+	edit := soaEditFor(model)
+
+	var soa *cscdm.ZoneSoaRecord
+	if edit == (cscdm.ZoneSoaEdit{}) {
+		zone, err := r.client.FetchZone(ctx, zoneName)
+		if err != nil {
+			return err
+		}
+		soa = &zone.SOA
+	} else {
+		var err error
+		if soa, err = r.client.PerformSoaEdit(ctx, zoneName, edit); err != nil {
+			return err
+		}
+	}
+
+	copySoa(model, soa)
+
+	return nil
+}
+
+// Create adopts the zone's SOA and applies the configured fields.
+func (r *SoaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SoaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.claim(plan.ZoneName.ValueString(), &resp.Diagnostics) {
+		return
+	}
+
+	if err := r.apply(ctx, &plan); err != nil {
+		r.client.ReleaseSoa(plan.ZoneName.ValueString())
+		resp.Diagnostics.AddError("error updating SOA", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *SoaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SoaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.claim(state.ZoneName.ValueString(), &resp.Diagnostics) {
+		return
+	}
+
+	zone, err := r.client.GetZone(state.ZoneName.ValueString())
+	if errors.Is(err, cscdm.ErrZoneNotFound) {
+		r.client.ReleaseSoa(state.ZoneName.ValueString())
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error reading zone", err.Error())
+		return
+	}
+
+	copySoa(&state, &zone.SOA)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update applies the configured SOA fields.
+func (r *SoaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan SoaResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.claim(plan.ZoneName.ValueString(), &resp.Diagnostics) {
+		return
+	}
+
+	if err := r.apply(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("error updating SOA", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the SOA from state. The SOA itself is left as it is, since
+// a zone cannot exist without one.
+func (r *SoaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SoaResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.client.ReleaseSoa(state.ZoneName.ValueString())
+}
+
+// ImportState imports a zone's SOA by zone name. The Read that follows
+// claims it.
+func (r *SoaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("zone_name"), req, resp)
+}
+
+// ModifyPlan rejects creating a cscdm_soa for a zone whose SOA another
+// resource has already claimed while being read or planned.
+func (r *SoaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var zoneName types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("zone_name"), &zoneName)...)
+	if resp.Diagnostics.HasError() || zoneName.IsUnknown() {
+		return
+	}
+
+	if r.client.SoaClaimed(zoneName.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("zone_name"),
+			"Duplicate SOA Resource",
+			fmt.Sprintf("The SOA of zone %s is already managed by another cscdm_soa resource. Each zone has a single SOA, so only one cscdm_soa may target it.", zoneName.ValueString()),
+		)
+	}
+}
+
+// claim claims the zone's SOA for this resource, reporting a clash with
+// another cscdm_soa in diags. Read and Update claim as well as Create, so a
+// resource already in state is seen by a new one for the same zone.
+func (r *SoaResource) claim(zoneName string, diags *diag.Diagnostics) bool {
+	if err := r.client.ClaimSoa(zoneName); err != nil {
+		diags.AddAttributeError(
+			path.Root("zone_name"),
+			"Duplicate SOA Resource",
+			fmt.Sprintf("%s by another cscdm_soa resource. Each zone has a single SOA, so only one cscdm_soa may target it.", err),
+		)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package provider_test
+
+import (
+	"context"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSoaResource_RejectsSecondResourceForZone(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		SOA:      cscdm.ZoneSoaRecord{Serial: 3, Refresh: 3600, TechEmail: "hostmaster.example.com"},
+	})
+
+	ctx := context.Background()
+	create := func() resource.CreateResponse {
+		r := provider.NewSoaResource()
+		r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: client}, &resource.ConfigureResponse{})
+
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		empty := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: empty}
+		plan.SetAttribute(ctx, path.Root("zone_name"), "example.com")
+
+		resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: empty}}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		return resp
+	}
+
+	first := create()
+	if first.Diagnostics.HasError() {
+		t.Fatalf("Create failed: %v", first.Diagnostics)
+	}
+
+	var refresh types.Int64
+	first.State.GetAttribute(ctx, path.Root("refresh"), &refresh)
+	if refresh.ValueInt64() != 3600 {
+		t.Errorf("Expected the adopted SOA in state, got refresh %s", refresh)
+	}
+
+	if second := create(); !second.Diagnostics.HasError() {
+		t.Errorf("Expected a second cscdm_soa for the same zone to be rejected")
+	}
+}
+
+func TestSoaResource_RejectsNewResourceForZoneInState(t *testing.T) {
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		SOA:      cscdm.ZoneSoaRecord{Serial: 3, Refresh: 3600, TechEmail: "hostmaster.example.com"},
+	})
+
+	ctx := context.Background()
+	newResource := func() (resource.Resource, tfsdk.Plan) {
+		r := provider.NewSoaResource()
+		r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: client}, &resource.ConfigureResponse{})
+
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		plan.SetAttribute(ctx, path.Root("zone_name"), "example.com")
+		return r, plan
+	}
+
+	// The existing resource is only read while planning.
+	existing, plan := newResource()
+	state := tfsdk.State(plan)
+	readResp := resource.ReadResponse{State: state}
+	existing.Read(ctx, resource.ReadRequest{State: state}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", readResp.Diagnostics)
+	}
+
+	added, plan := newResource()
+	emptyState := tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)}
+	modifyResp := resource.ModifyPlanResponse{Plan: plan}
+	added.(resource.ResourceWithModifyPlan).ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan, State: emptyState}, &modifyResp)
+	if !modifyResp.Diagnostics.HasError() {
+		t.Errorf("Expected planning a second cscdm_soa for the zone to be rejected")
+	}
+
+	createResp := resource.CreateResponse{State: emptyState}
+	added.Create(ctx, resource.CreateRequest{Plan: plan}, &createResp)
+	if !createResp.Diagnostics.HasError() {
+		t.Errorf("Expected creating a second cscdm_soa for the zone to be rejected")
+	}
+}
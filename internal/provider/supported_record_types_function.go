@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-cscdm/internal/cscdm"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ function.Function = &SupportedRecordTypesFunction{}
+)
+
+func NewSupportedRecordTypesFunction() function.Function {
+	return &SupportedRecordTypesFunction{}
+}
+
+// SupportedRecordTypesFunction lists the record types cscdm_record manages.
+type SupportedRecordTypesFunction struct{}
+
+func (f *SupportedRecordTypesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "supported_record_types"
+}
+
+func (f *SupportedRecordTypesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "List supported record types",
+		Description: "Returns the record types that `cscdm_record` can manage.",
+		Return:      function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *SupportedRecordTypesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, cscdm.SupportedRecordTypes()))
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &ZoneHclDataSource{}
+	_ datasource.DataSourceWithConfigure = &ZoneHclDataSource{}
+)
+
+func NewZoneHclDataSource() datasource.DataSource {
+	return &ZoneHclDataSource{}
+}
+
+// ZoneHclDataSource renders every manageable record of a zone as
+// cscdm_record resource blocks, for adopting an existing zone.
+type ZoneHclDataSource struct {
+	client *cscdm.Client
+}
+
+type ZoneHclDataSourceModel struct {
+	Zone     types.String `tfsdk:"zone"`
+	UseCache types.Bool   `tfsdk:"use_cache"`
+	Hcl      types.String `tfsdk:"hcl"`
+}
+
+func (d *ZoneHclDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_hcl"
+}
+
+func (d *ZoneHclDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Description: "Name of the zone to export.",
+				Required:    true,
+			},
+			"use_cache": schema.BoolAttribute{
+				Description: "Reuse a zone already cached by the provider during this run instead of reading it live. Defaults to `false`.",
+				Optional:    true,
+			},
+			"hcl": schema.StringAttribute{
+				Description: "A `cscdm_record` resource block for every record in the zone of a type the resource supports, ready to paste into a configuration. " +
+					"Resources are named `<zone>_<type>_<id>` with dots replaced by underscores, matching the import ID `<zone>:<type>:<id>`. " +
+					"Apex NS records are left out, since CSC manages them.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *ZoneHclDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ZoneHclDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ZoneHclDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var zone *cscdm.Zone
+	var err error
+	if state.UseCache.ValueBool() {
+		zone, err = d.client.GetZone(state.Zone.ValueString())
+	} else {
+		zone, err = d.client.RefreshZone(ctx, state.Zone.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zone, got error: %s", err))
+		return
+	}
+
+	state.Hcl = types.StringValue(renderZoneHcl(zone))
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// renderZoneHcl writes a cscdm_record block for each record in the zone that
+// the record resource can manage.
+func renderZoneHcl(zone *cscdm.Zone) string {
+	var b strings.Builder
+
+	for _, recordType := range cscdm.SupportedRecordTypes() {
+		for _, rec := range zoneRecordsByType(zone, recordType) {
+			if cscdm.CheckApexNs(zone.ZoneName, recordType, rec.Key) != nil {
+				continue
+			}
+
+			key := rec.Key
+			if key == "" {
+				key = "@"
+			}
+
+			attrs := [][2]string{
+				{"zone", hclString(zone.ZoneName)},
+				{"type", hclString(recordType)},
+				{"key", hclString(key)},
+				{"value", hclString(rec.Value)},
+			}
+			if rec.Ttl != 0 {
+				attrs = append(attrs, [2]string{"ttl", fmt.Sprint(rec.Ttl)})
+			}
+			if rec.Priority != 0 {
+				attrs = append(attrs, [2]string{"priority", fmt.Sprint(rec.Priority)})
+			}
+			if recordType == "SRV" {
+				attrs = append(attrs, [2]string{"weight", fmt.Sprint(rec.Weight)}, [2]string{"port", fmt.Sprint(rec.Port)})
+			}
+
+			// Align the equals signs the way terraform fmt would.
+			width := 0
+			for _, attr := range attrs {
+				width = max(width, len(attr[0]))
+			}
+
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "resource \"cscdm_record\" %s {\n", hclString(hclLabel(zone.ZoneName, recordType, rec.Id)))
+			for _, attr := range attrs {
+				fmt.Fprintf(&b, "  %-*s = %s\n", width, attr[0], attr[1])
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	return b.String()
+}
+
+// hclLabel builds a resource name from the zone, type and record id, keeping
+// only the characters HCL allows in identifiers.
+func hclLabel(zoneName string, recordType string, id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, fmt.Sprintf("%s_%s_%s", zoneName, recordType, id))
+}
+
+// hclString quotes s as an HCL string literal, escaping quotes, backslashes,
+// control characters and template sequences so TXT values survive intact.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case (c == '$' || c == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteByte(c)
+			b.WriteByte(c)
+		case c < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package provider_test
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestZoneHclDataSource_RendersRecordBlocks(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300}},
+		MX:       []cscdm.ZoneRecord{{Id: "301", Key: "@", Value: "mail.example.com", Priority: 10}},
+		NS: []cscdm.ZoneRecord{
+			{Id: "401", Key: "@", Value: "ns1.example.net"},
+			{Id: "402", Key: "sub", Value: "ns1.example.org"},
+		},
+		TXT: []cscdm.ZoneRecord{{Id: "501", Key: "", Value: `v="1" ${x} \ok`}},
+		SRV: []cscdm.ZoneRecord{{Id: "601", Key: "_sip._tcp", Value: "sip.example.com", Priority: 10, Weight: 5, Port: 5060}},
+	})
+
+	d := provider.NewZoneHclDataSource()
+	configurable, ok := d.(datasource.DataSourceWithConfigure)
+	if !ok {
+		t.Fatal("NewZoneHclDataSource does not support Configure")
+	}
+	configurable.Configure(ctx, datasource.ConfigureRequest{ProviderData: client}, &datasource.ConfigureResponse{})
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	configState := tfsdk.State(config)
+	if diags := configState.Set(ctx, &provider.ZoneHclDataSourceModel{
+		Zone:     types.StringValue("example.com"),
+		UseCache: types.BoolNull(),
+		Hcl:      types.StringNull(),
+	}); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+	config.Raw = configState.Raw
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+
+	var state provider.ZoneHclDataSourceModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("Failed to read state: %v", diags)
+	}
+	hcl := state.Hcl.ValueString()
+
+	for _, want := range []string{
+		"resource \"cscdm_record\" \"example_com_A_101\" {\n  zone  = \"example.com\"\n  type  = \"A\"\n  key   = \"www\"\n  value = \"10.0.0.1\"\n  ttl   = 300\n}\n",
+		"  key      = \"@\"\n  value    = \"mail.example.com\"\n  priority = 10\n",
+		"resource \"cscdm_record\" \"example_com_NS_402\" {",
+		"  key      = \"_sip._tcp\"\n  value    = \"sip.example.com\"\n  priority = 10\n  weight   = 5\n  port     = 5060\n",
+		`  key   = "@"` + "\n" + `  value = "v=\"1\" $${x} \\ok"`,
+	} {
+		if !strings.Contains(hcl, want) {
+			t.Errorf("Expected HCL to contain %q, got:\n%s", want, hcl)
+		}
+	}
+	if strings.Contains(hcl, "example_com_NS_401") {
+		t.Errorf("Expected the apex NS record to be left out, got:\n%s", hcl)
+	}
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-cscdm/internal/cscdm"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ datasource.DataSource              = &ZoneRecordsDataSource{}
+	_ datasource.DataSourceWithConfigure = &ZoneRecordsDataSource{}
+)
+
+func NewZoneRecordsDataSource() datasource.DataSource {
+	return &ZoneRecordsDataSource{}
+}
+
+// ZoneRecordsDataSource flattens every record of one zone into a map suited
+// to for_each.
+type ZoneRecordsDataSource struct {
+	client *cscdm.Client
+}
+
+type ZoneRecordsDataSourceModel struct {
+	Zone     types.String                `tfsdk:"zone"`
+	UseCache types.Bool                  `tfsdk:"use_cache"`
+	Records  map[string]ZoneRecordsEntry `tfsdk:"records"`
+}
+
+type ZoneRecordsEntry struct {
+	Type     types.String `tfsdk:"type"`
+	Id       types.String `tfsdk:"id"`
+	Key      types.String `tfsdk:"key"`
+	Value    types.String `tfsdk:"value"`
+	Ttl      types.Int64  `tfsdk:"ttl"`
+	Priority types.Int64  `tfsdk:"priority"`
+	Port     types.Int64  `tfsdk:"port"`
+	Status   types.String `tfsdk:"status"`
+}
+
+func (d *ZoneRecordsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_records"
+}
+
+func (d *ZoneRecordsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Description: "Name of the zone to read.",
+				Required:    true,
+			},
+			"use_cache": schema.BoolAttribute{
+				Description: "Reuse a zone already cached by the provider during this run instead of reading it live. Defaults to `false`.",
+				Optional:    true,
+			},
+			"records": schema.MapNestedAttribute{
+				Description: "Every record in the zone, of every type, keyed by `<type>/<key>/<value>`.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Description: "Record type.",
+							Computed:    true,
+						},
+						"id": schema.StringAttribute{
+							Computed: true,
+						},
+						"key": schema.StringAttribute{
+							Computed: true,
+						},
+						"value": schema.StringAttribute{
+							Computed: true,
+						},
+						"ttl": schema.Int64Attribute{
+							Computed: true,
+						},
+						"priority": schema.Int64Attribute{
+							Computed: true,
+						},
+						"port": schema.Int64Attribute{
+							Description: "Port of SRV records, null for other types.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ZoneRecordsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ZoneRecordsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ZoneRecordsDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var zone *cscdm.Zone
+	var err error
+	if state.UseCache.ValueBool() {
+		zone, err = d.client.GetZone(state.Zone.ValueString())
+	} else {
+		zone, err = d.client.RefreshZone(ctx, state.Zone.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zone, got error: %s", err))
+		return
+	}
+
+	state.Records = flattenZoneRecords(zone)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// flattenZoneRecords keys every record of the zone by type, key and value.
+func flattenZoneRecords(zone *cscdm.Zone) map[string]ZoneRecordsEntry {
+	records := make(map[string]ZoneRecordsEntry)
+
+	add := func(recordType string, rec cscdm.ZoneRecord, port types.Int64) {
+		records[fmt.Sprintf("%s/%s/%s", recordType, rec.Key, rec.Value)] = ZoneRecordsEntry{
+			Type:     types.StringValue(recordType),
+			Id:       types.StringValue(rec.Id),
+			Key:      types.StringValue(rec.Key),
+			Value:    types.StringValue(rec.Value),
+			Ttl:      types.Int64Value(rec.Ttl),
+			Priority: types.Int64Value(rec.Priority),
+			Port:     port,
+			Status:   types.StringValue(rec.Status),
+		}
+	}
+
+	for _, recordType := range zoneRecordTypes {
+		if recordType == "SRV" {
+			for _, rec := range zone.SRV {
+				add(recordType, rec, types.Int64Value(int64(rec.Port)))
+			}
+			continue
+		}
+
+		for _, rec := range zoneRecordsByType(zone, recordType) {
+			add(recordType, rec, types.Int64Null())
+		}
+	}
+
+	return records
+}
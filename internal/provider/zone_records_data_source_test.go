@@ -0,0 +1,70 @@
+package provider_test
+
+import (
+	"context"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestZoneRecordsDataSource_KeysRecordsByTypeKeyValue(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		A:        []cscdm.ZoneRecord{{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300}},
+		MX:       []cscdm.ZoneRecord{{Id: "301", Key: "@", Value: "mail.example.com", Priority: 10}},
+		SRV:      []cscdm.ZoneRecord{{Id: "401", Key: "_sip._tcp", Value: "sip.example.com", Port: 5060}},
+	})
+
+	d := provider.NewZoneRecordsDataSource()
+	configurable, ok := d.(datasource.DataSourceWithConfigure)
+	if !ok {
+		t.Fatal("NewZoneRecordsDataSource does not support Configure")
+	}
+	configurable.Configure(ctx, datasource.ConfigureRequest{ProviderData: client}, &datasource.ConfigureResponse{})
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	configState := tfsdk.State(config)
+	if diags := configState.Set(ctx, &provider.ZoneRecordsDataSourceModel{
+		Zone:     types.StringValue("example.com"),
+		UseCache: types.BoolNull(),
+	}); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+	config.Raw = configState.Raw
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+
+	var state provider.ZoneRecordsDataSourceModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("Failed to read state: %v", diags)
+	}
+
+	if len(state.Records) != 3 {
+		t.Errorf("Expected 3 records, got %d", len(state.Records))
+	}
+	if rec, ok := state.Records["A/www/10.0.0.1"]; !ok || rec.Id.ValueString() != "101" || !rec.Port.IsNull() {
+		t.Errorf("Expected A record 101 without a port, got %+v", rec)
+	}
+	if rec, ok := state.Records["MX/@/mail.example.com"]; !ok || rec.Priority.ValueInt64() != 10 {
+		t.Errorf("Expected MX record with priority 10, got %+v", rec)
+	}
+	if rec, ok := state.Records["SRV/_sip._tcp/sip.example.com"]; !ok || rec.Type.ValueString() != "SRV" || rec.Port.ValueInt64() != 5060 {
+		t.Errorf("Expected SRV record on port 5060, got %+v", rec)
+	}
+}
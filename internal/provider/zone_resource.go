@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-cscdm/internal/cscdm"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &ZoneResource{}
+	_ resource.ResourceWithConfigure   = &ZoneResource{}
+	_ resource.ResourceWithImportState = &ZoneResource{}
+)
+
+// NewZoneResource is a helper function to simplify the provider implementation.
+func NewZoneResource() resource.Resource {
+	return &ZoneResource{}
+}
+
+// ZoneResource is the resource implementation.
+type ZoneResource struct {
+	client *cscdm.Client
+}
+
+type ZoneResourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	ZoneName    types.String `tfsdk:"zone_name"`
+	HostingType types.String `tfsdk:"hosting_type"`
+	Status      types.String `tfsdk:"status"`
+	SOA         types.Object `tfsdk:"soa"`
+}
+
+// zoneSoaAttrTypes are the attribute types of ZoneSoaRecordModel.
+var zoneSoaAttrTypes = map[string]attr.Type{
+	"serial":       types.Int64Type,
+	"refresh":      types.Int64Type,
+	"retry":        types.Int64Type,
+	"expire":       types.Int64Type,
+	"ttl_min":      types.Int64Type,
+	"ttl_neg":      types.Int64Type,
+	"ttl_zone":     types.Int64Type,
+	"tech_email":   types.StringType,
+	"tech_mailbox": types.StringType,
+	"master_host":  types.StringType,
+}
+
+// Metadata returns the resource type name.
+func (r *ZoneResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone"
+}
+
+// Schema defines the schema for the resource.
+func (r *ZoneResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	soaAttributes := make(map[string]schema.Attribute, len(zoneSoaAttrTypes))
+	for name, attrType := range zoneSoaAttrTypes {
+		if attrType == types.StringType {
+			soaAttributes[name] = schema.StringAttribute{Computed: true}
+		} else {
+			soaAttributes[name] = schema.Int64Attribute{Computed: true}
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "A DNS zone in CSC Domain Manager. Deleting the resource deletes the zone and every record in it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hosting_type": schema.StringAttribute{
+				Description: "Hosting type to create the zone with. CSC's default is used when unset.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"soa": schema.SingleNestedAttribute{
+				Description: "The zone's SOA record as assigned by CSC.",
+				Computed:    true,
+				Attributes:  soaAttributes,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ZoneResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*cscdm.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// copyZone fills in the model from a zone read from CSC.
+func copyZone(ctx context.Context, dst *ZoneResourceModel, src *cscdm.Zone) diag.Diagnostics {
+	dst.Id = types.StringValue(src.ZoneName)
+	dst.ZoneName = types.StringValue(src.ZoneName)
+	dst.HostingType = types.StringValue(src.HostingType)
+	dst.Status = types.StringValue(src.Status)
+
+	soa, diags := types.ObjectValueFrom(ctx, zoneSoaAttrTypes, convertZoneSoaRecord(src.SOA))
+	dst.SOA = soa
+
+	return diags
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ZoneResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zone, err := r.client.CreateZone(ctx, plan.ZoneName.ValueString(), plan.HostingType.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error creating zone", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(copyZone(ctx, &plan, zone)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ZoneResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zone, err := r.client.FetchZone(ctx, state.ZoneName.ValueString())
+	if errors.Is(err, cscdm.ErrZoneNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error reading zone", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(copyZone(ctx, &state, zone)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called with a change, since every configurable attribute
+// requires replacement, but keeps the state in step with the plan.
+func (r *ZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ZoneResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the zone and removes the Terraform state on success. A zone
+// that is already gone is treated as deleted.
+func (r *ZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ZoneResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteZone(ctx, state.ZoneName.ValueString())
+	if err != nil && !errors.Is(err, cscdm.ErrZoneNotFound) {
+		resp.Diagnostics.AddError("error deleting zone", err.Error())
+	}
+}
+
+// ImportState imports a zone by name.
+func (r *ZoneResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("zone_name"), req, resp)
+}
@@ -0,0 +1,99 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestZoneResource_CreateAndDelete(t *testing.T) {
+	var mu sync.Mutex
+	zones := make(map[string]cscdm.Zone)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, "/zones/")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/zones":
+			var req cscdm.ZoneCreateReq
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			zones[req.ZoneName] = cscdm.Zone{
+				ZoneName:    req.ZoneName,
+				HostingType: req.HostingType,
+				Status:      "ACTIVE",
+				SOA:         cscdm.ZoneSoaRecord{Serial: 7, TechEmail: "hostmaster.example.com"},
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			delete(zones, name)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet:
+			zone, ok := zones[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(cscdm.ZoneEditErr{Code: "NOT_FOUND"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(zone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &cscdm.Client{BaseUrl: server.URL + "/"}
+	client.Configure("test-key", "test-token")
+	t.Cleanup(client.Stop)
+
+	ctx := context.Background()
+	r := provider.NewZoneResource()
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: client}, &resource.ConfigureResponse{})
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	empty := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: empty}
+	plan.SetAttribute(ctx, path.Root("zone_name"), "example.com")
+	plan.SetAttribute(ctx, path.Root("hosting_type"), "MANAGED")
+
+	createResp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: empty}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create failed: %v", createResp.Diagnostics)
+	}
+
+	var serial types.Int64
+	createResp.State.GetAttribute(ctx, path.Root("soa").AtName("serial"), &serial)
+	var id types.String
+	createResp.State.GetAttribute(ctx, path.Root("id"), &id)
+	if serial.ValueInt64() != 7 || id.ValueString() != "example.com" {
+		t.Errorf("Expected the created zone's SOA and id in state, got serial %s and id %s", serial, id)
+	}
+
+	deleteResp := resource.DeleteResponse{State: createResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: createResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete failed: %v", deleteResp.Diagnostics)
+	}
+
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() || !readResp.State.Raw.IsNull() {
+		t.Errorf("Expected reading the deleted zone to remove it from state, got %v", readResp.Diagnostics)
+	}
+}
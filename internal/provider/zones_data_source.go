@@ -2,16 +2,28 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"sort"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const (
+	// RECORD_FILTER_DEFAULT_MAX_RECORDS bounds the records a record_filter
+	// scans when max_records is not set.
+	RECORD_FILTER_DEFAULT_MAX_RECORDS = 100000
+)
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
 	_ datasource.DataSource              = &ZonesDataSource{}
@@ -24,26 +36,47 @@ func NewZonesDataSource() datasource.DataSource {
 
 // ZonesDataSource defines the data source implementation.
 type ZonesDataSource struct {
-	client *http.Client
+	client *cscdm.Client
 }
 
 type ZonesDataSourceModel struct {
-	Zones []ZoneModel  `tfsdk:"zones"`
-	Name  types.String `tfsdk:"name"`
+	Zones        []ZoneModel            `tfsdk:"zones"`
+	Name         types.String           `tfsdk:"name"`
+	Names        []types.String         `tfsdk:"names"`
+	UseCache     types.Bool             `tfsdk:"use_cache"`
+	RecordFilter *ZoneRecordFilterModel `tfsdk:"record_filter"`
+}
+
+// ZoneRecordFilterModel restricts the returned zones to those containing at
+// least one record matching every set field.
+type ZoneRecordFilterModel struct {
+	Type       types.String `tfsdk:"type"`
+	Key        types.String `tfsdk:"key"`
+	Value      types.String `tfsdk:"value"`
+	MaxRecords types.Int64  `tfsdk:"max_records"`
 }
 
 type ZoneModel struct {
-	ZoneName    types.String         `tfsdk:"zone_name"`
-	HostingType types.String         `tfsdk:"hosting_type"`
-	A           []ZoneRecordModel    `tfsdk:"a"`
-	AAAA        []ZoneRecordModel    `tfsdk:"aaaa"`
-	CNAME       []ZoneRecordModel    `tfsdk:"cname"`
-	MX          []ZoneRecordModel    `tfsdk:"mx"`
-	NS          []ZoneRecordModel    `tfsdk:"ns"`
-	TXT         []ZoneRecordModel    `tfsdk:"txt"`
-	SRV         []ZoneSrvRecordModel `tfsdk:"srv"`
-	CAA         []ZoneRecordModel    `tfsdk:"caa"`
-	SOA         ZoneSoaRecordModel   `tfsdk:"soa"`
+	ZoneName     types.String           `tfsdk:"zone_name"`
+	HostingType  types.String           `tfsdk:"hosting_type"`
+	Status       types.String           `tfsdk:"status"`
+	Nameservers  []types.String         `tfsdk:"nameservers"`
+	RecordCount  types.Int64            `tfsdk:"record_count"`
+	RecordCounts map[string]types.Int64 `tfsdk:"record_counts"`
+	RecordTypes  []types.String         `tfsdk:"present_record_types"`
+	A            []ZoneRecordModel      `tfsdk:"a"`
+	AAAA         []ZoneRecordModel      `tfsdk:"aaaa"`
+	CNAME        []ZoneRecordModel      `tfsdk:"cname"`
+	MX           []ZoneRecordModel      `tfsdk:"mx"`
+	NS           []ZoneRecordModel      `tfsdk:"ns"`
+	TXT          []ZoneRecordModel      `tfsdk:"txt"`
+	SRV          []ZoneSrvRecordModel   `tfsdk:"srv"`
+	CAA          []ZoneRecordModel      `tfsdk:"caa"`
+	TLSA         []ZoneTlsaRecordModel  `tfsdk:"tlsa"`
+	HINFO        []ZoneHinfoRecordModel `tfsdk:"hinfo"`
+	LOC          []ZoneLocRecordModel   `tfsdk:"loc"`
+	NAPTR        []ZoneNaptrRecordModel `tfsdk:"naptr"`
+	SOA          ZoneSoaRecordModel     `tfsdk:"soa"`
 }
 
 type ZoneRecordModel struct {
@@ -60,16 +93,51 @@ type ZoneSrvRecordModel struct {
 	Port types.Int32 `tfsdk:"port"`
 }
 
+type ZoneTlsaRecordModel struct {
+	ZoneRecordModel
+	Usage           types.Int64  `tfsdk:"usage"`
+	Selector        types.Int64  `tfsdk:"selector"`
+	MatchingType    types.Int64  `tfsdk:"matching_type"`
+	CertificateData types.String `tfsdk:"certificate_data"`
+}
+
+type ZoneHinfoRecordModel struct {
+	ZoneRecordModel
+	Cpu types.String `tfsdk:"cpu"`
+	Os  types.String `tfsdk:"os"`
+}
+
+type ZoneLocRecordModel struct {
+	ZoneRecordModel
+	Latitude            types.Float64 `tfsdk:"latitude"`
+	Longitude           types.Float64 `tfsdk:"longitude"`
+	Altitude            types.Float64 `tfsdk:"altitude"`
+	Size                types.Float64 `tfsdk:"size"`
+	HorizontalPrecision types.Float64 `tfsdk:"horizontal_precision"`
+	VerticalPrecision   types.Float64 `tfsdk:"vertical_precision"`
+}
+
+type ZoneNaptrRecordModel struct {
+	ZoneRecordModel
+	Order       types.Int64  `tfsdk:"order"`
+	Preference  types.Int64  `tfsdk:"preference"`
+	Flags       types.String `tfsdk:"flags"`
+	Service     types.String `tfsdk:"service"`
+	Regexp      types.String `tfsdk:"regexp"`
+	Replacement types.String `tfsdk:"replacement"`
+}
+
 type ZoneSoaRecordModel struct {
-	Serial     types.Int64  `tfsdk:"serial"`
-	Refresh    types.Int64  `tfsdk:"refresh"`
-	Retry      types.Int64  `tfsdk:"retry"`
-	Expire     types.Int64  `tfsdk:"expire"`
-	TtlMin     types.Int64  `tfsdk:"ttl_min"`
-	TtlNeg     types.Int64  `tfsdk:"ttl_neg"`
-	TtlZone    types.Int64  `tfsdk:"ttl_zone"`
-	TechEmail  types.String `tfsdk:"tech_email"`
-	MasterHost types.String `tfsdk:"master_host"`
+	Serial      types.Int64  `tfsdk:"serial"`
+	Refresh     types.Int64  `tfsdk:"refresh"`
+	Retry       types.Int64  `tfsdk:"retry"`
+	Expire      types.Int64  `tfsdk:"expire"`
+	TtlMin      types.Int64  `tfsdk:"ttl_min"`
+	TtlNeg      types.Int64  `tfsdk:"ttl_neg"`
+	TtlZone     types.Int64  `tfsdk:"ttl_zone"`
+	TechEmail   types.String `tfsdk:"tech_email"`
+	TechMailbox types.String `tfsdk:"tech_mailbox"`
+	MasterHost  types.String `tfsdk:"master_host"`
 }
 
 func (d *ZonesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -118,6 +186,50 @@ func (d *ZonesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 		},
 	}
 
+	TlsaRecordListAttrs := make(map[string]schema.Attribute)
+	for k, v := range RecordListAttrs {
+		TlsaRecordListAttrs[k] = v
+	}
+	TlsaRecordListAttrs["usage"] = schema.Int64Attribute{
+		Computed: true,
+	}
+	TlsaRecordListAttrs["selector"] = schema.Int64Attribute{
+		Computed: true,
+	}
+	TlsaRecordListAttrs["matching_type"] = schema.Int64Attribute{
+		Computed: true,
+	}
+	TlsaRecordListAttrs["certificate_data"] = schema.StringAttribute{
+		Computed: true,
+	}
+	TlsaRecordList := schema.ListNestedAttribute{
+		Computed: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: TlsaRecordListAttrs,
+		},
+	}
+
+	HinfoRecordList := extendRecordList(RecordListAttrs, map[string]schema.Attribute{
+		"cpu": schema.StringAttribute{Computed: true},
+		"os":  schema.StringAttribute{Computed: true},
+	})
+	LocRecordList := extendRecordList(RecordListAttrs, map[string]schema.Attribute{
+		"latitude":             schema.Float64Attribute{Computed: true},
+		"longitude":            schema.Float64Attribute{Computed: true},
+		"altitude":             schema.Float64Attribute{Computed: true},
+		"size":                 schema.Float64Attribute{Computed: true},
+		"horizontal_precision": schema.Float64Attribute{Computed: true},
+		"vertical_precision":   schema.Float64Attribute{Computed: true},
+	})
+	NaptrRecordList := extendRecordList(RecordListAttrs, map[string]schema.Attribute{
+		"order":       schema.Int64Attribute{Computed: true},
+		"preference":  schema.Int64Attribute{Computed: true},
+		"flags":       schema.StringAttribute{Computed: true},
+		"service":     schema.StringAttribute{Computed: true},
+		"regexp":      schema.StringAttribute{Computed: true},
+		"replacement": schema.StringAttribute{Computed: true},
+	})
+
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"zones": schema.ListNestedAttribute{
@@ -130,6 +242,29 @@ func (d *ZonesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 						"hosting_type": schema.StringAttribute{
 							Computed: true,
 						},
+						"status": schema.StringAttribute{
+							Description: "Zone status as reported by CSC, e.g. whether the zone is active or pending transfer.",
+							Computed:    true,
+						},
+						"nameservers": schema.ListAttribute{
+							Description: "Nameservers the zone is delegated to, taken from its apex NS records. Deduplicated and sorted.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"record_count": schema.Int64Attribute{
+							Description: "Total number of records in the zone across every record type listed here, excluding the SOA.",
+							Computed:    true,
+						},
+						"record_counts": schema.MapAttribute{
+							Description: "Number of records in the zone keyed by record type, e.g. `A` or `SRV`.",
+							ElementType: types.Int64Type,
+							Computed:    true,
+						},
+						"present_record_types": schema.ListAttribute{
+							Description: "Record types with at least one record in the zone, e.g. `A` or `SRV`, sorted. The SOA is not included.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
 						"a":     RecordList,
 						"aaaa":  RecordList,
 						"cname": RecordList,
@@ -138,6 +273,10 @@ func (d *ZonesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 						"txt":   RecordList,
 						"srv":   SrvRecordList,
 						"caa":   RecordList,
+						"tlsa":  TlsaRecordList,
+						"hinfo": HinfoRecordList,
+						"loc":   LocRecordList,
+						"naptr": NaptrRecordList,
 						"soa": schema.SingleNestedAttribute{
 							Computed: true,
 							Attributes: map[string]schema.Attribute{
@@ -163,7 +302,12 @@ func (d *ZonesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 									Computed: true,
 								},
 								"tech_email": schema.StringAttribute{
-									Computed: true,
+									Description: "Responsible person as reported by CSC, in DNS notation such as `hostmaster.example.com`.",
+									Computed:    true,
+								},
+								"tech_mailbox": schema.StringAttribute{
+									Description: "`tech_email` as a mailbox, such as `hostmaster@example.com`. Null when `tech_email` cannot be read as one.",
+									Computed:    true,
 								},
 								"master_host": schema.StringAttribute{
 									Computed: true,
@@ -176,6 +320,73 @@ func (d *ZonesDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 			"name": schema.StringAttribute{
 				Optional: true,
 			},
+			"names": schema.ListAttribute{
+				Description: "Names of the zones to read, returned in the order given. They are read concurrently, " +
+					"avoiding a listing of every zone when the ones needed are known. Conflicts with `name`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ConflictsWith(path.MatchRoot("name")),
+				},
+			},
+			"use_cache": schema.BoolAttribute{
+				Description: "Reuse zones already cached by the provider during this run instead of reading them live. " +
+					"Only applies when `name` or `names` is set. Defaults to `false`.",
+				Optional: true,
+			},
+			"record_filter": schema.SingleNestedAttribute{
+				Description: "Only return zones containing at least one record matching the filter. " +
+					"Filtering happens after the zones are fetched and scans each zone's records of the given type, " +
+					"stopping at the first match, so the cost grows linearly with the number of records of that type. " +
+					"The read fails once more records than `max_records` have been scanned.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Description: "Record type to match.",
+						Required:    true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(zoneRecordTypes...),
+						},
+					},
+					"key": schema.StringAttribute{
+						Description: "Record key to match exactly.",
+						Optional:    true,
+					},
+					"value": schema.StringAttribute{
+						Description: "Record value to match exactly, case-insensitively.",
+						Optional:    true,
+					},
+					"max_records": schema.Int64Attribute{
+						Description: fmt.Sprintf("Most records of the given type to scan across all zones before the read fails, "+
+							"so a filter over many large zones is caught rather than left to run. Defaults to `%d`.", RECORD_FILTER_DEFAULT_MAX_RECORDS),
+						Optional: true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// extendRecordList returns a computed record list with the common record
+// attributes plus the given type-specific ones.
+func extendRecordList(common map[string]schema.Attribute, extra map[string]schema.Attribute) schema.ListNestedAttribute {
+	attrs := make(map[string]schema.Attribute, len(common)+len(extra))
+	for k, v := range common {
+		attrs[k] = v
+	}
+	for k, v := range extra {
+		attrs[k] = v
+	}
+
+	return schema.ListNestedAttribute{
+		Computed: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: attrs,
 		},
 	}
 }
@@ -186,12 +397,12 @@ func (d *ZonesDataSource) Configure(ctx context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*http.Client)
+	client, ok := req.ProviderData.(*cscdm.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *cscdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -200,61 +411,17 @@ func (d *ZonesDataSource) Configure(ctx context.Context, req datasource.Configur
 	d.client = client
 }
 
-type ZonesJson struct {
-	Meta struct {
-		NumResults int64 `json:"numResults"`
-		Pages      int64 `json:"pages"`
-	} `json:"meta"`
-	Zones []ZoneJson `json:"zones"`
-	Links struct {
-		Self string `json:"self"`
-	} `json:"links"`
-}
-
-type ZoneJson struct {
-	ZoneName    string              `json:"zoneName"`
-	HostingType string              `json:"hostingType"`
-	A           []ZoneRecordJson    `json:"a"`
-	CNAME       []ZoneRecordJson    `json:"cname"`
-	AAAA        []ZoneRecordJson    `json:"aaaa"`
-	TXT         []ZoneRecordJson    `json:"txt"`
-	MX          []ZoneRecordJson    `json:"mx"`
-	NS          []ZoneRecordJson    `json:"ns"`
-	SRV         []ZoneSrvRecordJson `json:"srv"`
-	CAA         []ZoneRecordJson    `json:"caa"`
-	SOA         ZoneSoaRecordJson   `json:"soa"`
-}
-
-type ZoneRecordJson struct {
-	Id       string `json:"id"`
-	Key      string `json:"key"`
-	Value    string `json:"value"`
-	Ttl      int64  `json:"ttl,omitempty"`
-	Status   string `json:"status"`
-	Priority int64  `json:"priority"`
-}
-
-type ZoneSrvRecordJson struct {
-	ZoneRecordJson
-	Port int32 `json:"port"`
-}
-
-type ZoneSoaRecordJson struct {
-	Serial     int64  `json:"serial"`
-	Refresh    int64  `json:"refresh"`
-	Retry      int64  `json:"retry"`
-	Expire     int64  `json:"expire"`
-	TtlMin     int64  `json:"ttlMin"`
-	TtlNeg     int64  `json:"ttlNeg"`
-	TtlZone    int64  `json:"ttlZone"`
-	TechEmail  string `json:"techEmail"`
-	MasterHost string `json:"masterHost"`
-}
+func convertZone(zone cscdm.Zone) ZoneModel {
+	nameservers := []types.String{}
+	for _, nameserver := range zone.Nameservers() {
+		nameservers = append(nameservers, types.StringValue(nameserver))
+	}
 
-func convertZone(zone ZoneJson) ZoneModel {
-	return ZoneModel{
+	model := ZoneModel{
 		ZoneName:    types.StringValue(zone.ZoneName),
 		HostingType: types.StringValue(zone.HostingType),
+		Status:      types.StringValue(zone.Status),
+		Nameservers: nameservers,
 		A:           convertZoneRecords(zone.A),
 		AAAA:        convertZoneRecords(zone.AAAA),
 		CNAME:       convertZoneRecords(zone.CNAME),
@@ -263,11 +430,50 @@ func convertZone(zone ZoneJson) ZoneModel {
 		TXT:         convertZoneRecords(zone.TXT),
 		SRV:         convertZoneSrvRecords(zone.SRV),
 		CAA:         convertZoneRecords(zone.CAA),
+		TLSA:        convertZoneTlsaRecords(zone.TLSA),
+		HINFO:       convertZoneHinfoRecords(zone.HINFO),
+		LOC:         convertZoneLocRecords(zone.LOC),
+		NAPTR:       convertZoneNaptrRecords(zone.NAPTR),
 		SOA:         convertZoneSoaRecord(zone.SOA),
 	}
+
+	counts := map[string]int{
+		"A":     len(model.A),
+		"AAAA":  len(model.AAAA),
+		"CNAME": len(model.CNAME),
+		"MX":    len(model.MX),
+		"NS":    len(model.NS),
+		"TXT":   len(model.TXT),
+		"SRV":   len(model.SRV),
+		"CAA":   len(model.CAA),
+		"TLSA":  len(model.TLSA),
+		"HINFO": len(model.HINFO),
+		"LOC":   len(model.LOC),
+		"NAPTR": len(model.NAPTR),
+	}
+
+	total := 0
+	var present []string
+	model.RecordCounts = make(map[string]types.Int64, len(counts))
+	for recordType, count := range counts {
+		model.RecordCounts[recordType] = types.Int64Value(int64(count))
+		total += count
+		if count > 0 {
+			present = append(present, recordType)
+		}
+	}
+	model.RecordCount = types.Int64Value(int64(total))
+
+	sort.Strings(present)
+	model.RecordTypes = []types.String{}
+	for _, recordType := range present {
+		model.RecordTypes = append(model.RecordTypes, types.StringValue(recordType))
+	}
+
+	return model
 }
 
-func convertZoneRecord(rec ZoneRecordJson) ZoneRecordModel {
+func convertZoneRecord(rec cscdm.ZoneRecord) ZoneRecordModel {
 	return ZoneRecordModel{
 		Id:       types.StringValue(rec.Id),
 		Key:      types.StringValue(rec.Key),
@@ -278,7 +484,7 @@ func convertZoneRecord(rec ZoneRecordJson) ZoneRecordModel {
 	}
 }
 
-func convertZoneRecords(recs []ZoneRecordJson) []ZoneRecordModel {
+func convertZoneRecords(recs []cscdm.ZoneRecord) []ZoneRecordModel {
 	records := make([]ZoneRecordModel, len(recs))
 
 	for i, rec := range recs {
@@ -288,12 +494,12 @@ func convertZoneRecords(recs []ZoneRecordJson) []ZoneRecordModel {
 	return records
 }
 
-func convertZoneSrvRecords(recs []ZoneSrvRecordJson) []ZoneSrvRecordModel {
+func convertZoneSrvRecords(recs []cscdm.ZoneRecord) []ZoneSrvRecordModel {
 	records := make([]ZoneSrvRecordModel, len(recs))
 
 	for i, rec := range recs {
 		records[i] = ZoneSrvRecordModel{
-			ZoneRecordModel: convertZoneRecord(rec.ZoneRecordJson),
+			ZoneRecordModel: convertZoneRecord(rec),
 			Port:            types.Int32Value(rec.Port),
 		}
 	}
@@ -301,18 +507,215 @@ func convertZoneSrvRecords(recs []ZoneSrvRecordJson) []ZoneSrvRecordModel {
 	return records
 }
 
-func convertZoneSoaRecord(rec ZoneSoaRecordJson) ZoneSoaRecordModel {
+// convertZoneTlsaRecords splits each TLSA value into its fields, leaving
+// them null when CSC returns a value that does not parse.
+func convertZoneTlsaRecords(recs []cscdm.ZoneRecord) []ZoneTlsaRecordModel {
+	records := make([]ZoneTlsaRecordModel, len(recs))
+
+	for i, rec := range recs {
+		records[i] = ZoneTlsaRecordModel{
+			ZoneRecordModel: convertZoneRecord(rec),
+			Usage:           types.Int64Null(),
+			Selector:        types.Int64Null(),
+			MatchingType:    types.Int64Null(),
+			CertificateData: types.StringNull(),
+		}
+
+		tlsa, err := cscdm.ParseTlsaValue(rec.Value)
+		if err != nil {
+			continue
+		}
+
+		records[i].Usage = types.Int64Value(tlsa.Usage)
+		records[i].Selector = types.Int64Value(tlsa.Selector)
+		records[i].MatchingType = types.Int64Value(tlsa.MatchingType)
+		records[i].CertificateData = types.StringValue(tlsa.CertificateData)
+	}
+
+	return records
+}
+
+// convertZoneHinfoRecords splits each HINFO value into its fields, leaving
+// them null when the value cannot be parsed.
+func convertZoneHinfoRecords(recs []cscdm.ZoneRecord) []ZoneHinfoRecordModel {
+	records := make([]ZoneHinfoRecordModel, len(recs))
+
+	for i, rec := range recs {
+		records[i] = ZoneHinfoRecordModel{
+			ZoneRecordModel: convertZoneRecord(rec),
+			Cpu:             types.StringNull(),
+			Os:              types.StringNull(),
+		}
+
+		hinfo, err := cscdm.ParseHinfoValue(rec.Value)
+		if err != nil {
+			continue
+		}
+
+		records[i].Cpu = types.StringValue(hinfo.Cpu)
+		records[i].Os = types.StringValue(hinfo.Os)
+	}
+
+	return records
+}
+
+// convertZoneLocRecords splits each LOC value into its fields, leaving them
+// null when the value cannot be parsed.
+func convertZoneLocRecords(recs []cscdm.ZoneRecord) []ZoneLocRecordModel {
+	records := make([]ZoneLocRecordModel, len(recs))
+
+	for i, rec := range recs {
+		records[i] = ZoneLocRecordModel{
+			ZoneRecordModel:     convertZoneRecord(rec),
+			Latitude:            types.Float64Null(),
+			Longitude:           types.Float64Null(),
+			Altitude:            types.Float64Null(),
+			Size:                types.Float64Null(),
+			HorizontalPrecision: types.Float64Null(),
+			VerticalPrecision:   types.Float64Null(),
+		}
+
+		loc, err := cscdm.ParseLocValue(rec.Value)
+		if err != nil {
+			continue
+		}
+
+		records[i].Latitude = types.Float64Value(loc.Latitude)
+		records[i].Longitude = types.Float64Value(loc.Longitude)
+		records[i].Altitude = types.Float64Value(loc.Altitude)
+		records[i].Size = types.Float64Value(loc.Size)
+		records[i].HorizontalPrecision = types.Float64Value(loc.HorizontalPrecision)
+		records[i].VerticalPrecision = types.Float64Value(loc.VerticalPrecision)
+	}
+
+	return records
+}
+
+// convertZoneNaptrRecords splits each NAPTR value into its fields, leaving
+// them null when the value cannot be parsed.
+func convertZoneNaptrRecords(recs []cscdm.ZoneRecord) []ZoneNaptrRecordModel {
+	records := make([]ZoneNaptrRecordModel, len(recs))
+
+	for i, rec := range recs {
+		records[i] = ZoneNaptrRecordModel{
+			ZoneRecordModel: convertZoneRecord(rec),
+			Order:           types.Int64Null(),
+			Preference:      types.Int64Null(),
+			Flags:           types.StringNull(),
+			Service:         types.StringNull(),
+			Regexp:          types.StringNull(),
+			Replacement:     types.StringNull(),
+		}
+
+		naptr, err := cscdm.ParseNaptrValue(rec.Value)
+		if err != nil {
+			continue
+		}
+
+		records[i].Order = types.Int64Value(naptr.Order)
+		records[i].Preference = types.Int64Value(naptr.Preference)
+		records[i].Flags = types.StringValue(naptr.Flags)
+		records[i].Service = types.StringValue(naptr.Service)
+		records[i].Regexp = types.StringValue(naptr.Regexp)
+		records[i].Replacement = types.StringValue(naptr.Replacement)
+	}
+
+	return records
+}
+
+func convertZoneSoaRecord(rec cscdm.ZoneSoaRecord) ZoneSoaRecordModel {
+	techMailbox := types.StringNull()
+	if mailbox, err := cscdm.SoaEmailToMailbox(rec.TechEmail); err == nil {
+		techMailbox = types.StringValue(mailbox)
+	}
+
 	return ZoneSoaRecordModel{
-		Serial:     types.Int64Value(rec.Serial),
-		Refresh:    types.Int64Value(rec.Refresh),
-		Retry:      types.Int64Value(rec.Retry),
-		Expire:     types.Int64Value(rec.Expire),
-		TtlMin:     types.Int64Value(rec.TtlMin),
-		TtlNeg:     types.Int64Value(rec.TtlNeg),
-		TtlZone:    types.Int64Value(rec.TtlZone),
-		TechEmail:  types.StringValue(rec.TechEmail),
-		MasterHost: types.StringValue(rec.MasterHost),
+		Serial:      types.Int64Value(rec.Serial),
+		Refresh:     types.Int64Value(rec.Refresh),
+		Retry:       types.Int64Value(rec.Retry),
+		Expire:      types.Int64Value(rec.Expire),
+		TtlMin:      types.Int64Value(rec.TtlMin),
+		TtlNeg:      types.Int64Value(rec.TtlNeg),
+		TtlZone:     types.Int64Value(rec.TtlZone),
+		TechEmail:   types.StringValue(rec.TechEmail),
+		TechMailbox: techMailbox,
+		MasterHost:  types.StringValue(rec.MasterHost),
+	}
+}
+
+// zoneRecordTypes lists the record types read from zones, including those
+// cscdm_record cannot manage.
+var zoneRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "SRV", "CAA", "TLSA", "HINFO", "LOC", "NAPTR"}
+
+func zoneRecordsByType(zone *cscdm.Zone, recordType string) []cscdm.ZoneRecord {
+	switch recordType {
+	case "A":
+		return zone.A
+	case "AAAA":
+		return zone.AAAA
+	case "CNAME":
+		return zone.CNAME
+	case "MX":
+		return zone.MX
+	case "NS":
+		return zone.NS
+	case "TXT":
+		return zone.TXT
+	case "CAA":
+		return zone.CAA
+	case "TLSA":
+		return zone.TLSA
+	case "HINFO":
+		return zone.HINFO
+	case "LOC":
+		return zone.LOC
+	case "NAPTR":
+		return zone.NAPTR
+	case "SRV":
+		return zone.SRV
+	default:
+		return nil
+	}
+}
+
+// recordFilterScan applies a record filter to zones in turn, counting the
+// records scanned against the filter's max_records.
+type recordFilterScan struct {
+	filter  *ZoneRecordFilterModel
+	scanned int64
+}
+
+// matches reports whether the zone holds at least one record matching the
+// filter. A nil filter matches every zone. It fails once more records have
+// been scanned than the filter allows.
+func (s *recordFilterScan) matches(zone *cscdm.Zone) (bool, error) {
+	if s.filter == nil {
+		return true, nil
+	}
+
+	limit := int64(RECORD_FILTER_DEFAULT_MAX_RECORDS)
+	if !s.filter.MaxRecords.IsNull() {
+		limit = s.filter.MaxRecords.ValueInt64()
+	}
+
+	for _, rec := range zoneRecordsByType(zone, s.filter.Type.ValueString()) {
+		s.scanned++
+		if s.scanned > limit {
+			return false, fmt.Errorf("record_filter scanned more than %d %s records; narrow the zones read with name or names, or raise max_records", limit, s.filter.Type.ValueString())
+		}
+
+		if !s.filter.Key.IsNull() && rec.Key != s.filter.Key.ValueString() {
+			continue
+		}
+
+		if !s.filter.Value.IsNull() && !strings.EqualFold(rec.Value, s.filter.Value.ValueString()) {
+			continue
+		}
+
+		return true, nil
 	}
+
+	return false, nil
 }
 
 func (d *ZonesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -325,35 +728,62 @@ func (d *ZonesDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	scan := recordFilterScan{filter: state.RecordFilter}
+	appendMatching := func(zone *cscdm.Zone) bool {
+		ok, err := scan.matches(zone)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("record_filter").AtName("max_records"), "Record Filter Too Broad", err.Error())
+			return false
+		}
+		if ok {
+			state.Zones = append(state.Zones, convertZone(*zone))
+		}
+		return true
+	}
+
 	if state.Name != types.StringNull() {
-		var zoneJson ZoneJson
-		zonesResp, err := d.client.Get(fmt.Sprintf("zones/%s", state.Name.ValueString()))
+		// Reading a single zone live also refreshes the shared cache so that
+		// records read later in the same run see any out-of-band changes.
+		var zone *cscdm.Zone
+		var err error
+		if state.UseCache.ValueBool() {
+			zone, err = d.client.GetZone(state.Name.ValueString())
+		} else {
+			zone, err = d.client.RefreshZone(ctx, state.Name.ValueString())
+		}
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read desired zone, got error: %s", err))
 			return
 		}
-		defer zonesResp.Body.Close()
-		err = json.NewDecoder(zonesResp.Body).Decode(&zoneJson)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to unmarshal desired zone, got error: %s", err))
+		if !appendMatching(zone) {
 			return
 		}
-		state.Zones = append(state.Zones, convertZone(zoneJson))
-	} else {
-		var zonesJson ZonesJson
-		zonesResp, err := d.client.Get("zones")
+	} else if state.Names != nil {
+		zoneNames := make([]string, len(state.Names))
+		for i, name := range state.Names {
+			zoneNames[i] = name.ValueString()
+		}
+
+		zones, err := d.client.GetZones(ctx, zoneNames, !state.UseCache.ValueBool())
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zones, got error: %s", err))
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read desired zones, got error: %s", err))
 			return
 		}
-		defer zonesResp.Body.Close()
-		err = json.NewDecoder(zonesResp.Body).Decode(&zonesJson)
+		for _, zone := range zones {
+			if !appendMatching(zone) {
+				return
+			}
+		}
+	} else {
+		zones, err := d.client.ListZones(ctx)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to unmarshal zones, got error: %s", err))
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read zones, got error: %s", err))
 			return
 		}
-		for _, zone := range zonesJson.Zones {
-			state.Zones = append(state.Zones, convertZone(zone))
+		for i := range zones {
+			if !appendMatching(&zones[i]) {
+				return
+			}
 		}
 	}
 
@@ -0,0 +1,129 @@
+package provider_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"terraform-provider-cscdm/internal/cscdm"
+	"terraform-provider-cscdm/internal/provider"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readZones reads the zones data source configured with model.
+func readZones(t *testing.T, client *cscdm.Client, model *provider.ZonesDataSourceModel) datasource.ReadResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	d := provider.NewZonesDataSource()
+	configurable, ok := d.(datasource.DataSourceWithConfigure)
+	if !ok {
+		t.Fatal("NewZonesDataSource does not support Configure")
+	}
+	configurable.Configure(ctx, datasource.ConfigureRequest{ProviderData: client}, &datasource.ConfigureResponse{})
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	configState := tfsdk.State(config)
+	if diags := configState.Set(ctx, model); diags.HasError() {
+		t.Fatalf("Failed to build config: %v", diags)
+	}
+	config.Raw = configState.Raw
+
+	// The framework hands Read the configuration as its starting state.
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: config.Raw}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+
+	return resp
+}
+
+func TestZonesDataSource_ListsPresentRecordTypes(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, cscdm.Zone{
+		ZoneName: "example.com",
+		TXT:      []cscdm.ZoneRecord{{Id: "201", Key: "@", Value: "v=spf1 -all"}},
+		A:        []cscdm.ZoneRecord{{Id: "101", Key: "www", Value: "10.0.0.1", Ttl: 300}},
+		SRV:      []cscdm.ZoneRecord{{Id: "401", Key: "_sip._tcp", Value: "sip.example.com", Port: 5060}},
+	})
+
+	resp := readZones(t, client, &provider.ZonesDataSourceModel{
+		Name:     types.StringValue("example.com"),
+		UseCache: types.BoolNull(),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+
+	var state provider.ZonesDataSourceModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("Failed to read state: %v", diags)
+	}
+
+	if len(state.Zones) != 1 {
+		t.Fatalf("Expected 1 zone, got %d", len(state.Zones))
+	}
+
+	var present []string
+	for _, recordType := range state.Zones[0].RecordTypes {
+		present = append(present, recordType.ValueString())
+	}
+	if expected := []string{"A", "SRV", "TXT"}; !slices.Equal(present, expected) {
+		t.Errorf("Expected present record types %v, got %v", expected, present)
+	}
+}
+
+func TestZonesDataSource_RecordFilterBoundsScan(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t,
+		cscdm.Zone{
+			ZoneName: "a.example",
+			A:        []cscdm.ZoneRecord{{Id: "1", Key: "www", Value: "10.0.0.1"}, {Id: "2", Key: "api", Value: "10.0.0.2"}},
+		},
+		cscdm.Zone{
+			ZoneName: "b.example",
+			A:        []cscdm.ZoneRecord{{Id: "3", Key: "www", Value: "10.0.0.3"}, {Id: "4", Key: "api", Value: "10.0.0.9"}},
+		},
+	)
+	model := func(maxRecords int64) *provider.ZonesDataSourceModel {
+		return &provider.ZonesDataSourceModel{
+			Names:    []types.String{types.StringValue("a.example"), types.StringValue("b.example")},
+			UseCache: types.BoolNull(),
+			RecordFilter: &provider.ZoneRecordFilterModel{
+				Type:       types.StringValue("A"),
+				Key:        types.StringNull(),
+				Value:      types.StringValue("10.0.0.9"),
+				MaxRecords: types.Int64Value(maxRecords),
+			},
+		}
+	}
+
+	// Finding the match takes scanning all four records.
+	resp := readZones(t, client, model(4))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read failed: %v", resp.Diagnostics)
+	}
+	var state provider.ZonesDataSourceModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("Failed to read state: %v", diags)
+	}
+	if len(state.Zones) != 1 || state.Zones[0].ZoneName.ValueString() != "b.example" {
+		t.Errorf("Expected only b.example to match, got %+v", state.Zones)
+	}
+
+	resp = readZones(t, client, model(3))
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected the read to fail once max_records was exceeded")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "more than 3 A records") {
+		t.Errorf("Expected the error to name the bound, got %q", detail)
+	}
+}